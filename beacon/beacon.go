@@ -0,0 +1,198 @@
+package beacon
+
+// 参考实现:
+// drand: Distributed Randomness Beacon. https://drand.love
+//
+// 随机数信标按轮次产生随机数,第round轮的值是门限BLS签名:
+//   - 链式模式: m_round = SHA-256(σ_{round-1} || round),σ_0为创世种子GenesisSeed
+//   - 非链式模式: m_round = SHA-256(round)
+//   - 签名: σ_round = h(m_round)^x,x为群私钥,由任意Threshold个部分签名插值得到
+//   - 随机数: randomness = SHA-256(σ_round)
+//
+// 其中round编码为8字节大端整数,σ编码为压缩的G2点(BN254上为64字节)。
+// BLS签名是唯一的,因此每一轮的随机数由群公钥和轮次(以及链式模式下的上一轮签名)唯一确定,
+// 任何少于Threshold个节点的联盟都无法预测或操纵。
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"github.com/mmsyan/GnarkPairingProject/bls"
+	"runtime"
+)
+
+// Mode 表示信标是否将上一轮的签名链接进本轮的消息。
+type Mode int
+
+const (
+	ModeChained Mode = iota
+	ModeUnchained
+)
+
+// String 返回模式的名称。
+func (mode Mode) String() string {
+	switch mode {
+	case ModeChained:
+		return "chained"
+	case ModeUnchained:
+		return "unchained"
+	default:
+		return fmt.Sprintf("Mode(%d)", int(mode))
+	}
+}
+
+// ChainInfo 表示一条信标链的公开信息。
+// GenesisSeed只在链式模式下使用,作为第1轮的上一轮签名。
+type ChainInfo struct {
+	Mode           Mode
+	GroupPublicKey bls.G1Point
+	Threshold      int
+	GenesisSeed    []byte
+}
+
+// Beacon 表示信标第Round轮的输出,PreviousSignature只在链式模式下使用。
+type Beacon struct {
+	Round             uint64
+	PreviousSignature []byte
+	Signature         bls.G2Point
+}
+
+// Randomness 返回本轮的随机数: SHA-256(σ_round)。
+func (beacon *Beacon) Randomness() []byte {
+	randomness := sha256.Sum256(beacon.Signature.Bytes())
+	return randomness[:]
+}
+
+// Message 计算第round轮被签名的消息。
+func Message(mode Mode, round uint64, previousSignature []byte) ([]byte, error) {
+	var roundBytes [8]byte
+	binary.BigEndian.PutUint64(roundBytes[:], round)
+	h := sha256.New()
+	switch mode {
+	case ModeChained:
+		h.Write(previousSignature)
+	case ModeUnchained:
+	default:
+		return nil, fmt.Errorf("unsupported beacon mode: %v", mode)
+	}
+	h.Write(roundBytes[:])
+	return h.Sum(nil), nil
+}
+
+// PartialSignRound 使用私钥分片对第round轮产生部分签名,非链式模式下忽略previousSignature。
+func PartialSignRound(blsParams bls.BLSParams, info ChainInfo, share bls.ThresholdKeyShare, round uint64, previousSignature []byte) (*bls.PartialSignature, error) {
+	message, err := Message(info.Mode, round, previousSignature)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign beacon round %d: %v", round, err)
+	}
+	partialSignature, err := bls.PartialSign(blsParams, share, message)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign beacon round %d: %v", round, err)
+	}
+	return partialSignature, nil
+}
+
+// VerifyPartialRound 使用公钥分片验证第round轮的部分签名。
+func VerifyPartialRound(blsParams bls.BLSParams, info ChainInfo, sharePublicKey bls.G1Point, round uint64, previousSignature []byte, partialSignature bls.PartialSignature) (bool, error) {
+	message, err := Message(info.Mode, round, previousSignature)
+	if err != nil {
+		return false, fmt.Errorf("failed to verify beacon round %d: %v", round, err)
+	}
+	return bls.PartialVerify(blsParams, sharePublicKey, message, partialSignature)
+}
+
+// CombineRound 将Threshold个部分签名合并为第round轮的信标,并使用群公钥验证结果。
+func CombineRound(blsParams bls.BLSParams, info ChainInfo, round uint64, previousSignature []byte, partialSignatures []bls.PartialSignature) (*Beacon, error) {
+	message, err := Message(info.Mode, round, previousSignature)
+	if err != nil {
+		return nil, fmt.Errorf("failed to combine beacon round %d: %v", round, err)
+	}
+	signature, err := bls.CombinePartialSignatures(blsParams, info.Threshold, message, partialSignatures)
+	if err != nil {
+		return nil, fmt.Errorf("failed to combine beacon round %d: %v", round, err)
+	}
+	beacon := newBeacon(info, round, previousSignature, signature.Signature)
+	isValid, err := VerifyBeacon(blsParams, info, *beacon)
+	if err != nil {
+		return nil, fmt.Errorf("failed to combine beacon round %d: %v", round, err)
+	}
+	if !isValid {
+		return nil, fmt.Errorf("failed to combine beacon round %d: invalid partial signatures", round)
+	}
+	return beacon, nil
+}
+
+// VerifyBeacon 使用群公钥验证单轮信标。链式模式下只检查签名覆盖了beacon.PreviousSignature,
+// 不检查它确实是上一轮的签名,需要检查链接时使用VerifyChain。
+func VerifyBeacon(blsParams bls.BLSParams, info ChainInfo, beacon Beacon) (bool, error) {
+	if beacon.Round == 0 {
+		return false, nil
+	}
+	if info.Mode == ModeChained && len(beacon.PreviousSignature) == 0 {
+		return false, nil
+	}
+	message, err := Message(info.Mode, beacon.Round, beacon.PreviousSignature)
+	if err != nil {
+		return false, fmt.Errorf("failed to verify beacon round %d: %v", beacon.Round, err)
+	}
+	return bls.DetachedVerify(blsParams, info.GroupPublicKey, message, beacon.Signature)
+}
+
+// VerifyChain 验证一段连续的信标: 轮次必须连续递增,链式模式下每一轮的PreviousSignature必须是上一轮的签名,
+// 从第1轮开始的链段还要求第1轮的PreviousSignature为创世种子。
+// 所有签名通过bls.BatchVerify批量验证。
+func VerifyChain(blsParams bls.BLSParams, info ChainInfo, beacons []Beacon) (bool, error) {
+	if len(beacons) == 0 {
+		return false, fmt.Errorf("failed to verify beacon chain: no beacons")
+	}
+	publicKeys := make([]bls.G1Point, len(beacons))
+	signatures := make([]bls.BLSSignature, len(beacons))
+	for i, beacon := range beacons {
+		if beacon.Round == 0 {
+			return false, nil
+		}
+		if i > 0 && beacon.Round != beacons[i-1].Round+1 {
+			return false, nil
+		}
+		if info.Mode == ModeChained {
+			var expectedPrevious []byte
+			switch {
+			case i > 0:
+				expectedPrevious = beacons[i-1].Signature.Bytes()
+			case beacon.Round == 1:
+				expectedPrevious = info.GenesisSeed
+			default:
+				expectedPrevious = beacon.PreviousSignature
+			}
+			if len(beacon.PreviousSignature) == 0 || !bytes.Equal(beacon.PreviousSignature, expectedPrevious) {
+				return false, nil
+			}
+		}
+		message, err := Message(info.Mode, beacon.Round, beacon.PreviousSignature)
+		if err != nil {
+			return false, fmt.Errorf("failed to verify beacon chain: %v", err)
+		}
+		publicKeys[i] = info.GroupPublicKey
+		signatures[i] = bls.BLSSignature{
+			Message:   message,
+			Signature: beacon.Signature,
+		}
+	}
+	isValid, _, err := bls.BatchVerify(blsParams, publicKeys, signatures, runtime.GOMAXPROCS(0))
+	if err != nil {
+		return false, fmt.Errorf("failed to verify beacon chain: %v", err)
+	}
+	return isValid, nil
+}
+
+func newBeacon(info ChainInfo, round uint64, previousSignature []byte, signature bls.G2Point) *Beacon {
+	beacon := &Beacon{
+		Round:     round,
+		Signature: signature,
+	}
+	if info.Mode == ModeChained {
+		beacon.PreviousSignature = append([]byte{}, previousSignature...)
+	}
+	return beacon
+}
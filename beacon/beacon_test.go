@@ -0,0 +1,174 @@
+package beacon
+
+import (
+	"bytes"
+	"github.com/mmsyan/GnarkPairingProject/bls"
+	"github.com/mmsyan/GnarkPairingProject/dkg"
+	"testing"
+)
+
+func newLocalBeacon(t *testing.T, mode Mode) (*bls.BLSParams, *LocalBeacon) {
+	params, err := bls.SetUpWithCiphersuite(bls.CiphersuiteBasic)
+	if err != nil {
+		t.Fatalf("SetUpWithCiphersuite failed: %v", err)
+	}
+	keyPair, shares, err := bls.ThresholdKeyGeneration(*params, 3, 5)
+	if err != nil {
+		t.Fatalf("ThresholdKeyGeneration failed: %v", err)
+	}
+	info := ChainInfo{
+		Mode:           mode,
+		GroupPublicKey: keyPair.PublicKey,
+		Threshold:      3,
+		GenesisSeed:    []byte("test network genesis"),
+	}
+	local, err := NewLocalBeacon(*params, info, shares)
+	if err != nil {
+		t.Fatalf("NewLocalBeacon failed: %v", err)
+	}
+	return params, local
+}
+
+func generateRounds(t *testing.T, local *LocalBeacon, rounds int) []Beacon {
+	beacons := make([]Beacon, rounds)
+	for i := range beacons {
+		beacon, err := local.Next()
+		if err != nil {
+			t.Fatalf("Next failed: %v", err)
+		}
+		beacons[i] = *beacon
+	}
+	return beacons
+}
+
+// TestChainedBeacon 测试链式信标的产生与验证,以及篡改链接或轮次后验证失败。
+func TestChainedBeacon(t *testing.T) {
+	params, local := newLocalBeacon(t, ModeChained)
+	info := local.Info()
+	beacons := generateRounds(t, local, 5)
+
+	for i, beacon := range beacons {
+		if beacon.Round != uint64(i+1) {
+			t.Fatalf("unexpected round %d at position %d", beacon.Round, i)
+		}
+		isValid, err := VerifyBeacon(*params, info, beacon)
+		if err != nil || !isValid {
+			t.Fatalf("VerifyBeacon failed for round %d: %v", beacon.Round, err)
+		}
+		if i > 0 && bytes.Equal(beacon.Randomness(), beacons[i-1].Randomness()) {
+			t.Fatal("consecutive rounds produced the same randomness")
+		}
+	}
+	if !bytes.Equal(beacons[0].PreviousSignature, info.GenesisSeed) {
+		t.Fatal("the first round is not linked to the genesis seed")
+	}
+	if local.Latest().Round != 5 {
+		t.Fatalf("unexpected latest round %d", local.Latest().Round)
+	}
+
+	isValid, err := VerifyChain(*params, info, beacons)
+	if err != nil || !isValid {
+		t.Fatalf("VerifyChain failed: %v", err)
+	}
+	// 不从第1轮开始的链段
+	isValid, err = VerifyChain(*params, info, beacons[2:])
+	if err != nil || !isValid {
+		t.Fatalf("VerifyChain failed on a segment: %v", err)
+	}
+
+	// 跳过一轮
+	skipped := append(append([]Beacon{}, beacons[:2]...), beacons[3:]...)
+	if isValid, _ := VerifyChain(*params, info, skipped); isValid {
+		t.Fatal("VerifyChain accepted a chain with a missing round")
+	}
+	// 签名有效但链接到错误的上一轮签名
+	relinked := append([]Beacon{}, beacons...)
+	relinked[3].PreviousSignature = beacons[1].PreviousSignature
+	if isValid, _ := VerifyChain(*params, info, relinked); isValid {
+		t.Fatal("VerifyChain accepted a broken link")
+	}
+	// 替换一轮的签名
+	forged := append([]Beacon{}, beacons...)
+	forged[4].Signature = beacons[3].Signature
+	if isValid, _ := VerifyBeacon(*params, info, forged[4]); isValid {
+		t.Fatal("VerifyBeacon accepted a forged signature")
+	}
+	if isValid, _ := VerifyChain(*params, info, forged); isValid {
+		t.Fatal("VerifyChain accepted a forged signature")
+	}
+}
+
+// TestUnchainedBeacon 测试非链式信标: 每一轮的消息只依赖轮次,可以独立验证。
+func TestUnchainedBeacon(t *testing.T) {
+	params, local := newLocalBeacon(t, ModeUnchained)
+	info := local.Info()
+	beacons := generateRounds(t, local, 3)
+	for _, beacon := range beacons {
+		if beacon.PreviousSignature != nil {
+			t.Fatal("unchained beacons must not carry the previous signature")
+		}
+	}
+	isValid, err := VerifyChain(*params, info, beacons)
+	if err != nil || !isValid {
+		t.Fatalf("VerifyChain failed: %v", err)
+	}
+
+	// 非链式模式下每一轮的签名与上一轮无关
+	message, err := Message(ModeUnchained, 3, nil)
+	if err != nil {
+		t.Fatalf("Message failed: %v", err)
+	}
+	isValid, err = bls.DetachedVerify(*params, info.GroupPublicKey, message, beacons[2].Signature)
+	if err != nil || !isValid {
+		t.Fatalf("DetachedVerify failed: %v", err)
+	}
+	moved := beacons[2]
+	moved.Round = 4
+	if isValid, _ := VerifyBeacon(*params, info, moved); isValid {
+		t.Fatal("VerifyBeacon accepted a signature for another round")
+	}
+}
+
+// TestBeaconFromDKG 测试使用DKG输出的私钥分片运行信标。
+func TestBeaconFromDKG(t *testing.T) {
+	dkgParams, err := dkg.NewParams(4, 3)
+	if err != nil {
+		t.Fatalf("NewParams failed: %v", err)
+	}
+	transport := dkg.NewInMemoryTransport(4)
+	participants := make([]*dkg.Participant, 4)
+	for i := range participants {
+		participants[i], err = dkg.NewParticipant(dkgParams, i+1, transport)
+		if err != nil {
+			t.Fatalf("NewParticipant failed: %v", err)
+		}
+	}
+	keyShares, err := dkg.Run(participants)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	params, err := bls.SetUp()
+	if err != nil {
+		t.Fatalf("SetUp failed: %v", err)
+	}
+	shares := make([]bls.ThresholdKeyShare, len(keyShares))
+	for i, keyShare := range keyShares {
+		shares[i] = keyShare.BLSKeyShare()
+	}
+	info := ChainInfo{
+		Mode:           ModeChained,
+		GroupPublicKey: bls.NewG1Point(keyShares[0].GroupPublicKey),
+		Threshold:      3,
+		GenesisSeed:    []byte("dkg genesis"),
+	}
+	local, err := NewLocalBeacon(*params, info, shares)
+	if err != nil {
+		t.Fatalf("NewLocalBeacon failed: %v", err)
+	}
+	beacons := generateRounds(t, local, 2)
+	isValid, err := VerifyChain(*params, info, beacons)
+	if err != nil || !isValid {
+		t.Fatalf("VerifyChain failed: %v", err)
+	}
+}
@@ -0,0 +1,84 @@
+package beacon
+
+import (
+	"fmt"
+	"github.com/mmsyan/GnarkPairingProject/bls"
+	"sync"
+)
+
+// LocalBeacon 在单个进程中模拟持有私钥分片的信标节点,用于测试网络。
+// 每一轮所有节点产生部分签名,验证通过的前Threshold个部分签名被合并为信标。
+// 可以被多个goroutine并发使用。
+type LocalBeacon struct {
+	mutex     sync.Mutex
+	blsParams bls.BLSParams
+	info      ChainInfo
+	shares    []bls.ThresholdKeyShare
+	latest    *Beacon
+}
+
+// NewLocalBeacon 由私钥分片创建本地信标,分片可以来自bls.ThresholdKeyGeneration或dkg包。
+func NewLocalBeacon(blsParams bls.BLSParams, info ChainInfo, shares []bls.ThresholdKeyShare) (*LocalBeacon, error) {
+	if info.Threshold < 1 || len(shares) < info.Threshold {
+		return nil, fmt.Errorf("failed to create local beacon: need at least %d shares, got %d", info.Threshold, len(shares))
+	}
+	if info.Mode == ModeChained && len(info.GenesisSeed) == 0 {
+		return nil, fmt.Errorf("failed to create local beacon: chained mode requires a genesis seed")
+	}
+	return &LocalBeacon{
+		blsParams: blsParams,
+		info:      info,
+		shares:    shares,
+	}, nil
+}
+
+// Info 返回信标链的公开信息。
+func (local *LocalBeacon) Info() ChainInfo {
+	return local.info
+}
+
+// Latest 返回最近产生的信标,尚未产生任何信标时返回nil。
+func (local *LocalBeacon) Latest() *Beacon {
+	local.mutex.Lock()
+	defer local.mutex.Unlock()
+	return local.latest
+}
+
+// Next 产生下一轮的信标。
+func (local *LocalBeacon) Next() (*Beacon, error) {
+	local.mutex.Lock()
+	defer local.mutex.Unlock()
+
+	round := uint64(1)
+	previousSignature := local.info.GenesisSeed
+	if local.latest != nil {
+		round = local.latest.Round + 1
+		previousSignature = local.latest.Signature.Bytes()
+	}
+
+	var partialSignatures []bls.PartialSignature
+	for _, share := range local.shares {
+		partialSignature, err := PartialSignRound(local.blsParams, local.info, share, round, previousSignature)
+		if err != nil {
+			return nil, err
+		}
+		isValid, err := VerifyPartialRound(local.blsParams, local.info, share.PublicKey, round, previousSignature, *partialSignature)
+		if err != nil {
+			return nil, err
+		}
+		if !isValid {
+			continue
+		}
+		partialSignatures = append(partialSignatures, *partialSignature)
+		if len(partialSignatures) == local.info.Threshold {
+			break
+		}
+	}
+
+	beacon, err := CombineRound(local.blsParams, local.info, round, previousSignature, partialSignatures)
+	if err != nil {
+		return nil, err
+	}
+	local.latest = beacon
+	return beacon, nil
+}
@@ -4,85 +4,134 @@ import (
 	"crypto/rand"
 	"fmt"
 	"github.com/consensys/gnark-crypto/ecc"
-	"github.com/consensys/gnark-crypto/ecc/bn254"
 	"math/big"
 )
 
+// BLSParams 为BLS签名的参数,Curve选择所使用的曲线,G1Generator与G2Generator必须位于该曲线上。
 type BLSParams struct {
-	G1Generator bn254.G1Affine
+	G1Generator G1Point
+	G2Generator G2Point
 	DST         []byte
+	PopDST      []byte
+	PrehashDST  []byte
+	Ciphersuite Ciphersuite
+	Variant     Variant
+	Curve       ecc.ID
 }
 
 type BLSKeyPair struct {
 	PrivateKey *big.Int
-	PublicKey  bn254.G1Affine
+	PublicKey  G1Point
 }
 
 type BLSSignature struct {
 	Message   []byte
-	Signature bn254.G2Affine
+	Signature G2Point
 }
 
 // BLS签名初始化操作
-// 返回域的大小、G1群的生成元、BLS签名的DST
+// 返回BN254上G1群和G2群的生成元、BLS签名的DST、持有性证明(PoP)的DST、先哈希后签名的DST
+// 其他曲线使用SetUpOnCurve
 func SetUp() (*BLSParams, error) {
-	_, _, g1, _ := bn254.Generators()
-	return &BLSParams{
-		G1Generator: g1,
-		DST:         []byte("bls Signature"),
-	}, nil
+	return SetUpOnCurve(ecc.BN254)
 }
 
 func KeyGeneration(blsParams BLSParams) (*BLSKeyPair, error) {
-	x, err := rand.Int(rand.Reader, ecc.BN254.ScalarField())
+	backend, err := backendOf(blsParams, VariantMinPublicKeySize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate key pair: %v", err)
+	}
+	x, err := generatePrivateKey(blsParams, backend.curve().ScalarField())
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate key pair: %v", err)
 	}
-	var g1x bn254.G1Affine
-	g1x.ScalarMultiplication(&blsParams.G1Generator, x)
 
 	// private key: x <- Zq
 	// public key: g1^x
 	return &BLSKeyPair{
 		PrivateKey: x,
-		PublicKey:  g1x,
+		PublicKey:  SkToPk(blsParams, x),
 	}, nil
 }
 
 func Sign(blsParams BLSParams, privateKey *big.Int, message []byte) (*BLSSignature, error) {
-	// compute h(m): message to point
-	hm, err := bn254.HashToG2(message, blsParams.DST)
+	hmx, err := signWithDST(blsParams, privateKey, message, blsParams.DST)
 	if err != nil {
 		return nil, fmt.Errorf("failed to sign message: %v", err)
 	}
-	var hmx bn254.G2Affine
-	// compute h(m)^x
-	hmx.ScalarMultiplication(&hm, privateKey)
 
 	// bls signature: (m, h(m)^x)
 	return &BLSSignature{
 		Message:   message,
-		Signature: hmx,
+		Signature: *hmx,
 	}, nil
 }
 
-func Verify(blsParams BLSParams, publicKey bn254.G1Affine, blsSignature BLSSignature) (bool, error) {
-	hm, err := bn254.HashToG2(blsSignature.Message, blsParams.DST)
+func Verify(blsParams BLSParams, publicKey G1Point, blsSignature BLSSignature) (bool, error) {
+	isValid, err := verifyWithDST(blsParams, publicKey, blsSignature.Message, blsSignature.Signature, blsParams.DST)
 	if err != nil {
-		return false, fmt.Errorf("failed to verify signature: %v", err)
+		return false, fmt.Errorf("failed to verify signature: %w", err)
+	}
+	return isValid, nil
+}
+
+// signWithDST 计算h(m)^x,h使用给定的DST将消息哈希到G2。
+func signWithDST(blsParams BLSParams, privateKey *big.Int, message []byte, dst []byte) (*G2Point, error) {
+	backend, err := backendOf(blsParams, VariantMinPublicKeySize)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPrivateKey(backend, privateKey); err != nil {
+		return nil, err
+	}
+	// MESSAGE-AUGMENTATION方案下签名的是pk || m
+	signedMessage := augmentMessage(blsParams, SkToPk(blsParams, privateKey), message)
+	// compute h(m): message to point
+	hm, err := backend.g2().hashToCurve(signedMessage, dst)
+	if err != nil {
+		return nil, err
+	}
+	// compute h(m)^x
+	return &G2Point{hm.mul(privateKey)}, nil
+}
+
+// verifyWithDST 检查e(publicKey, h(m)) = e(G1Generator, signature),h使用给定的DST。
+// 公钥与签名都必须通过有效性检查,否则返回*ValidationError:
+// 公钥与签名同时为无穷远点时,配对等式对任意消息都成立。
+func verifyWithDST(blsParams BLSParams, publicKey G1Point, message []byte, signature G2Point, dst []byte) (bool, error) {
+	backend, err := backendOf(blsParams, VariantMinPublicKeySize)
+	if err != nil {
+		return false, err
+	}
+	if err := ValidatePublicKey(blsParams, publicKey); err != nil {
+		return false, err
+	}
+	if err := ValidateSignature(blsParams, signature); err != nil {
+		return false, err
+	}
+	signedMessage := augmentMessage(blsParams, publicKey, message)
+	hm, err := backend.g2().hashToCurve(signedMessage, dst)
+	if err != nil {
+		return false, err
 	}
 
-	var negSignature bn254.G2Affine
-	negSignature.Neg(&blsSignature.Signature)
 	// e(g1^x, h(m)) =?= e(g1, h(m)^x)
 	// e(publicKey, hm) =?= e(G1Generator, Signature)
 	// e(publicKey, hm) * e(G1Generator, negSignature) =?= 1
-	isValid, err := bn254.PairingCheck(
-		[]bn254.G1Affine{publicKey, blsParams.G1Generator},
-		[]bn254.G2Affine{hm, negSignature},
+	return backend.pairingCheck(
+		[]point{publicKey.p, blsParams.G1Generator.p},
+		[]point{hm, signature.p.neg()},
 	)
-	if err != nil {
-		return false, fmt.Errorf("failed to verify signature: %v", err)
+}
+
+// generatePrivateKey 在标量域[0, r)中随机生成私钥。规范方案下使用随机IKM通过KeyGen派生私钥。
+func generatePrivateKey(blsParams BLSParams, r *big.Int) (*big.Int, error) {
+	if blsParams.Ciphersuite == CiphersuiteNone {
+		return rand.Int(rand.Reader, r)
 	}
-	return isValid, nil
+	ikm := make([]byte, 32)
+	if _, err := rand.Read(ikm); err != nil {
+		return nil, err
+	}
+	return hkdfModR(ikm, nil, r)
 }
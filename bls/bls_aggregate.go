@@ -0,0 +1,105 @@
+package bls
+
+import (
+	"fmt"
+)
+
+// Aggregate 将多个BLS签名聚合为一个G2群上的点,所有签名必须位于同一条曲线上。
+// 聚合签名为各签名之和: sigma = sigma_1 + sigma_2 + ... + sigma_n
+// 聚合后的签名需要配合各自的(公钥, 消息)对,通过AggregateVerify进行验证。
+func Aggregate(signatures []BLSSignature) (*G2Point, error) {
+	if len(signatures) == 0 {
+		return nil, fmt.Errorf("failed to aggregate signatures: empty signature set")
+	}
+	points := make([]point, len(signatures))
+	for i := range signatures {
+		points[i] = signatures[i].Signature.p
+	}
+	// sigma = sum(sigma_i)
+	aggregateSignature, err := sumPoints(points)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate signatures: %w", err)
+	}
+	return &G2Point{aggregateSignature}, nil
+}
+
+// AggregateVerify 验证针对不同消息的聚合签名。
+// publicKeys[i]与messages[i]一一对应。在原有参数和BASIC方案下要求所有消息互不相同,
+// 否则拒绝验证(防止重复消息攻击);MESSAGE-AUGMENTATION方案下实际签名的消息为pk_i || m_i,
+// PROOF-OF-POSSESSION方案下公钥已经过持有性证明,这两种方案都不要求消息互不相同。
+// 验证等式为: e(pk_1, h(m_1)) * ... * e(pk_n, h(m_n)) =?= e(G1Generator, sigma)
+// 所有配对通过一次多重配对检查完成。
+func AggregateVerify(blsParams BLSParams, publicKeys []G1Point, messages [][]byte, aggregateSignature G2Point) (bool, error) {
+	backend, err := backendOf(blsParams, VariantMinPublicKeySize)
+	if err != nil {
+		return false, fmt.Errorf("failed to verify aggregate signature: %v", err)
+	}
+	if len(publicKeys) == 0 {
+		return false, fmt.Errorf("failed to verify aggregate signature: empty public key set")
+	}
+	if len(publicKeys) != len(messages) {
+		return false, fmt.Errorf("failed to verify aggregate signature: %d public keys but %d messages", len(publicKeys), len(messages))
+	}
+	for i := range publicKeys {
+		if err := ValidatePublicKey(blsParams, publicKeys[i]); err != nil {
+			return false, fmt.Errorf("failed to verify aggregate signature: public key %d: %w", i, err)
+		}
+	}
+	if err := ValidateSignature(blsParams, aggregateSignature); err != nil {
+		return false, fmt.Errorf("failed to verify aggregate signature: %w", err)
+	}
+	if err := checkDistinctMessages(blsParams, messages); err != nil {
+		return false, fmt.Errorf("failed to verify aggregate signature: %v", err)
+	}
+
+	g1s := make([]point, 0, len(publicKeys)+1)
+	g2s := make([]point, 0, len(publicKeys)+1)
+	for i, message := range messages {
+		hm, err := backend.g2().hashToCurve(augmentMessage(blsParams, publicKeys[i], message), blsParams.DST)
+		if err != nil {
+			return false, fmt.Errorf("failed to verify aggregate signature: %v", err)
+		}
+		g1s = append(g1s, publicKeys[i].p)
+		g2s = append(g2s, hm)
+	}
+
+	// e(pk_1, h(m_1)) * ... * e(pk_n, h(m_n)) * e(G1Generator, -sigma) =?= 1
+	g1s = append(g1s, blsParams.G1Generator.p)
+	g2s = append(g2s, aggregateSignature.p.neg())
+	isValid, err := backend.pairingCheck(g1s, g2s)
+	if err != nil {
+		return false, fmt.Errorf("failed to verify aggregate signature: %v", err)
+	}
+	return isValid, nil
+}
+
+// checkDistinctMessages 在原有参数和BASIC方案下检查消息是否互不相同。
+func checkDistinctMessages(blsParams BLSParams, messages [][]byte) error {
+	if blsParams.Ciphersuite != CiphersuiteNone && blsParams.Ciphersuite != CiphersuiteBasic {
+		return nil
+	}
+	seen := make(map[string]bool, len(messages))
+	for _, message := range messages {
+		if seen[string(message)] {
+			return fmt.Errorf("duplicate message")
+		}
+		seen[string(message)] = true
+	}
+	return nil
+}
+
+// sumPoints 计算同一个群中的非空点集之和,点不在同一条曲线上时返回ErrCurveMismatch。
+func sumPoints(points []point) (point, error) {
+	if points[0] == nil {
+		return nil, ErrCurveMismatch
+	}
+	curve := points[0].curve()
+	sum := points[0]
+	for _, p := range points[1:] {
+		if curveOfPoint(p) != curve {
+			return nil, ErrCurveMismatch
+		}
+		sum = sum.add(p)
+	}
+	return sum, nil
+}
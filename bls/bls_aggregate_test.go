@@ -0,0 +1,87 @@
+package bls
+
+import (
+	"fmt"
+	"testing"
+)
+
+// TestAggregateFlow 测试多个签名者对不同消息签名后的聚合与验证。
+func TestAggregateFlow(t *testing.T) {
+	params, err := SetUp()
+	if err != nil {
+		t.Fatalf("SetUp failed: %v", err)
+	}
+
+	n := 5
+	publicKeys := make([]G1Point, n)
+	messages := make([][]byte, n)
+	signatures := make([]BLSSignature, n)
+	for i := 0; i < n; i++ {
+		keyPair, err := KeyGeneration(*params)
+		if err != nil {
+			t.Fatalf("KeyGeneration failed: %v", err)
+		}
+		messages[i] = []byte(fmt.Sprintf("block %d", i))
+		signature, err := Sign(*params, keyPair.PrivateKey, messages[i])
+		if err != nil {
+			t.Fatalf("Sign failed: %v", err)
+		}
+		publicKeys[i] = keyPair.PublicKey
+		signatures[i] = *signature
+	}
+
+	aggregateSignature, err := Aggregate(signatures)
+	if err != nil {
+		t.Fatalf("Aggregate failed: %v", err)
+	}
+	isValid, err := AggregateVerify(*params, publicKeys, messages, *aggregateSignature)
+	if err != nil {
+		t.Fatalf("AggregateVerify failed unexpectedly: %v", err)
+	}
+	if !isValid {
+		t.Fatal("aggregate signature was expected to be valid")
+	}
+
+	// 交换两个公钥后验证应失败
+	publicKeys[0], publicKeys[1] = publicKeys[1], publicKeys[0]
+	isValid, err = AggregateVerify(*params, publicKeys, messages, *aggregateSignature)
+	if err != nil {
+		t.Fatalf("AggregateVerify failed unexpectedly: %v", err)
+	}
+	if isValid {
+		t.Fatal("aggregate signature was expected to be invalid")
+	}
+}
+
+// TestAggregateVerifyDuplicateMessages 测试包含重复消息的聚合签名会被拒绝。
+func TestAggregateVerifyDuplicateMessages(t *testing.T) {
+	params, err := SetUp()
+	if err != nil {
+		t.Fatalf("SetUp failed: %v", err)
+	}
+
+	message := []byte("same message")
+	publicKeys := make([]G1Point, 2)
+	signatures := make([]BLSSignature, 2)
+	for i := 0; i < 2; i++ {
+		keyPair, err := KeyGeneration(*params)
+		if err != nil {
+			t.Fatalf("KeyGeneration failed: %v", err)
+		}
+		signature, err := Sign(*params, keyPair.PrivateKey, message)
+		if err != nil {
+			t.Fatalf("Sign failed: %v", err)
+		}
+		publicKeys[i] = keyPair.PublicKey
+		signatures[i] = *signature
+	}
+
+	aggregateSignature, err := Aggregate(signatures)
+	if err != nil {
+		t.Fatalf("Aggregate failed: %v", err)
+	}
+	_, err = AggregateVerify(*params, publicKeys, [][]byte{message, message}, *aggregateSignature)
+	if err == nil {
+		t.Fatal("AggregateVerify was expected to reject duplicate messages")
+	}
+}
@@ -0,0 +1,257 @@
+package bls
+
+// 签名聚合器(attestation pool)
+//
+// 委员会的成员按索引对同一消息签名,Aggregator并发地接收各成员的签名:
+//   - 每个签名先单独验证,无效的签名不会被聚合
+//   - 已参与的成员记录在位图中,重复提交的签名被忽略
+//   - Aggregate输出携带位图的聚合签名AggregateAttestation,可以序列化,并由验证者根据委员会公钥列表验证
+//
+// PROOF-OF-POSSESSION方案下使用FastAggregateVerify验证,委员会公钥需事先通过PopVerify检查;
+// MESSAGE-AUGMENTATION方案下各成员实际签名的消息不同,使用AggregateVerify验证;
+// BASIC方案要求聚合的消息互不相同,不支持对同一消息的聚合;原有参数(CiphersuiteNone)没有持有性证明的约束,
+// 同消息聚合无法抵抗恶意密钥攻击,同样不支持。
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+	"sync"
+)
+
+// Bitfield 委员会成员的参与位图,第i个成员对应第i/8个字节的第i%8位(低位在前)。
+type Bitfield []byte
+
+// NewBitfield 创建可以容纳size个成员的空位图。
+func NewBitfield(size int) Bitfield {
+	return make(Bitfield, (size+7)/8)
+}
+
+// Get 返回第i个成员是否参与。
+func (b Bitfield) Get(i int) bool {
+	return b[i/8]&(1<<uint(i%8)) != 0
+}
+
+// Set 标记第i个成员参与。
+func (b Bitfield) Set(i int) {
+	b[i/8] |= 1 << uint(i%8)
+}
+
+// Count 返回参与成员的数量。
+func (b Bitfield) Count() int {
+	count := 0
+	for _, v := range b {
+		count += bits.OnesCount8(v)
+	}
+	return count
+}
+
+// AggregateAttestation 委员会对同一消息的聚合签名,Participants标记了参与聚合的成员。
+type AggregateAttestation struct {
+	Message      []byte
+	Participants Bitfield
+	Signature    G2Point
+}
+
+// Aggregator 并发安全的签名聚合器。
+type Aggregator struct {
+	blsParams BLSParams
+	committee []G1Point
+	message   []byte
+
+	mu           sync.Mutex
+	participants Bitfield
+	// 初始为G2的无穷远点
+	aggregate point
+}
+
+// NewAggregator 创建聚合委员会成员对message的签名的聚合器,committee[i]为第i个成员的公钥。
+func NewAggregator(blsParams BLSParams, committee []G1Point, message []byte) (*Aggregator, error) {
+	backend, err := checkAggregatorParams(blsParams)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create aggregator: %v", err)
+	}
+	if len(committee) == 0 {
+		return nil, fmt.Errorf("failed to create aggregator: empty committee")
+	}
+	return &Aggregator{
+		blsParams:    blsParams,
+		committee:    append([]G1Point{}, committee...),
+		message:      append([]byte{}, message...),
+		participants: NewBitfield(len(committee)),
+		aggregate:    backend.g2().infinity(),
+	}, nil
+}
+
+// Add 验证第index个成员的签名并将其聚合。
+// 签名有效且该成员第一次提交时返回true;成员已经参与过时返回false,签名被忽略;签名无效时返回错误。
+// 签名验证在锁外进行,多个goroutine可以同时调用Add。
+func (aggregator *Aggregator) Add(index int, signature G2Point) (bool, error) {
+	if index < 0 || index >= len(aggregator.committee) {
+		return false, fmt.Errorf("failed to add signature: index %d out of range [0, %d)", index, len(aggregator.committee))
+	}
+	if aggregator.Has(index) {
+		return false, nil
+	}
+	// Verify检查签名在参数曲线上、位于子群中且不是无穷远点
+	isValid, err := Verify(aggregator.blsParams, aggregator.committee[index], BLSSignature{
+		Message:   aggregator.message,
+		Signature: signature,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to add signature: %w", err)
+	}
+	if !isValid {
+		return false, fmt.Errorf("failed to add signature: invalid signature from member %d", index)
+	}
+
+	aggregator.mu.Lock()
+	defer aggregator.mu.Unlock()
+	// 验证期间同一成员的签名可能已经被其他goroutine聚合
+	if aggregator.participants.Get(index) {
+		return false, nil
+	}
+	aggregator.participants.Set(index)
+	aggregator.aggregate = aggregator.aggregate.add(signature.p)
+	return true, nil
+}
+
+// AddSignature 与Add相同,但要求BLSSignature中的消息与聚合器的消息一致。
+func (aggregator *Aggregator) AddSignature(index int, blsSignature BLSSignature) (bool, error) {
+	if string(blsSignature.Message) != string(aggregator.message) {
+		return false, fmt.Errorf("failed to add signature: message does not match the aggregator")
+	}
+	return aggregator.Add(index, blsSignature.Signature)
+}
+
+// Has 返回第index个成员是否已经参与聚合。
+func (aggregator *Aggregator) Has(index int) bool {
+	aggregator.mu.Lock()
+	defer aggregator.mu.Unlock()
+	return aggregator.participants.Get(index)
+}
+
+// Count 返回已经参与聚合的成员数量。
+func (aggregator *Aggregator) Count() int {
+	aggregator.mu.Lock()
+	defer aggregator.mu.Unlock()
+	return aggregator.participants.Count()
+}
+
+// Aggregate 返回当前的聚合签名,之后仍然可以继续添加签名。
+func (aggregator *Aggregator) Aggregate() (*AggregateAttestation, error) {
+	aggregator.mu.Lock()
+	defer aggregator.mu.Unlock()
+	if aggregator.participants.Count() == 0 {
+		return nil, fmt.Errorf("failed to aggregate signatures: no signatures added")
+	}
+	attestation := &AggregateAttestation{
+		Message:      append([]byte{}, aggregator.message...),
+		Participants: append(Bitfield{}, aggregator.participants...),
+		Signature:    G2Point{aggregator.aggregate},
+	}
+	return attestation, nil
+}
+
+// VerifyAggregateAttestation 根据委员会公钥列表验证聚合签名,位图的长度必须与委员会的大小一致。
+func VerifyAggregateAttestation(blsParams BLSParams, committee []G1Point, attestation AggregateAttestation) (bool, error) {
+	if _, err := checkAggregatorParams(blsParams); err != nil {
+		return false, fmt.Errorf("failed to verify aggregate attestation: %v", err)
+	}
+	if err := checkBitfield(attestation.Participants, len(committee)); err != nil {
+		return false, fmt.Errorf("failed to verify aggregate attestation: %v", err)
+	}
+	publicKeys := make([]G1Point, 0, attestation.Participants.Count())
+	for i := range committee {
+		if attestation.Participants.Get(i) {
+			publicKeys = append(publicKeys, committee[i])
+		}
+	}
+	if len(publicKeys) == 0 {
+		return false, fmt.Errorf("failed to verify aggregate attestation: no participants")
+	}
+
+	var isValid bool
+	var err error
+	if blsParams.Ciphersuite == CiphersuiteMessageAugmentation {
+		messages := make([][]byte, len(publicKeys))
+		for i := range messages {
+			messages[i] = attestation.Message
+		}
+		isValid, err = AggregateVerify(blsParams, publicKeys, messages, attestation.Signature)
+	} else {
+		isValid, err = FastAggregateVerify(blsParams, publicKeys, attestation.Message, attestation.Signature)
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to verify aggregate attestation: %v", err)
+	}
+	return isValid, nil
+}
+
+// MarshalAggregateAttestation 编码聚合签名:
+// 消息长度(4字节大端) || 消息 || 位图长度(4字节大端) || 位图 || 压缩的G2签名(BN254上为64字节)。
+func MarshalAggregateAttestation(attestation AggregateAttestation) []byte {
+	signature := attestation.Signature.Bytes()
+	data := make([]byte, 0, 8+len(attestation.Message)+len(attestation.Participants)+len(signature))
+	data = binary.BigEndian.AppendUint32(data, uint32(len(attestation.Message)))
+	data = append(data, attestation.Message...)
+	data = binary.BigEndian.AppendUint32(data, uint32(len(attestation.Participants)))
+	data = append(data, attestation.Participants...)
+	return append(data, signature...)
+}
+
+// UnmarshalAggregateAttestation 解码MarshalAggregateAttestation的输出,签名必须位于G2的素数阶子群中。
+func UnmarshalAggregateAttestation(blsParams BLSParams, data []byte) (*AggregateAttestation, error) {
+	message, rest, err := readLengthPrefixed(data)
+	if err != nil {
+		return nil, &DecodeError{Object: "aggregate attestation", Err: err}
+	}
+	participants, rest, err := readLengthPrefixed(rest)
+	if err != nil {
+		return nil, &DecodeError{Object: "aggregate attestation", Err: err}
+	}
+	signature, err := UnmarshalSignature(blsParams, rest)
+	if err != nil {
+		return nil, err
+	}
+	return &AggregateAttestation{
+		Message:      message,
+		Participants: Bitfield(participants),
+		Signature:    *signature,
+	}, nil
+}
+
+// readLengthPrefixed 读取4字节大端长度前缀的字段,返回字段内容与剩余的数据。
+func readLengthPrefixed(data []byte) ([]byte, []byte, error) {
+	if len(data) < 4 {
+		return nil, nil, ErrInvalidLength
+	}
+	length := binary.BigEndian.Uint32(data)
+	data = data[4:]
+	if uint64(length) > uint64(len(data)) {
+		return nil, nil, ErrInvalidLength
+	}
+	return append([]byte{}, data[:length]...), data[length:], nil
+}
+
+// checkBitfield 检查位图的长度与委员会大小一致,且超出委员会大小的填充位为0。
+func checkBitfield(participants Bitfield, size int) error {
+	if len(participants) != (size+7)/8 {
+		return fmt.Errorf("bitfield has %d bytes, expected %d for a committee of %d", len(participants), (size+7)/8, size)
+	}
+	if size%8 != 0 && participants[len(participants)-1]>>uint(size%8) != 0 {
+		return fmt.Errorf("bitfield has bits set beyond the committee size %d", size)
+	}
+	return nil
+}
+
+func checkAggregatorParams(blsParams BLSParams) (curveBackend, error) {
+	backend, err := backendOf(blsParams, VariantMinPublicKeySize)
+	if err != nil {
+		return nil, err
+	}
+	if blsParams.Ciphersuite != CiphersuiteProofOfPossession && blsParams.Ciphersuite != CiphersuiteMessageAugmentation {
+		return nil, fmt.Errorf("ciphersuite %v does not support aggregating signatures on the same message", blsParams.Ciphersuite)
+	}
+	return backend, nil
+}
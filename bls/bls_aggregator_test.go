@@ -0,0 +1,185 @@
+package bls
+
+import (
+	"bytes"
+	"errors"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"sync"
+	"testing"
+)
+
+// generateCommittee 生成size个委员会成员的密钥与对message的签名。
+func generateCommittee(t *testing.T, params BLSParams, size int, message []byte) ([]G1Point, []G2Point) {
+	committee := make([]G1Point, size)
+	signatures := make([]G2Point, size)
+	for i := range committee {
+		keyPair, err := KeyGeneration(params)
+		if err != nil {
+			t.Fatalf("KeyGeneration failed: %v", err)
+		}
+		committee[i] = keyPair.PublicKey
+		signature, err := Sign(params, keyPair.PrivateKey, message)
+		if err != nil {
+			t.Fatalf("Sign failed: %v", err)
+		}
+		signatures[i] = signature.Signature
+	}
+	return committee, signatures
+}
+
+// TestAggregatorConcurrent 测试多个goroutine同时提交签名(包括重复提交)时的聚合结果。
+func TestAggregatorConcurrent(t *testing.T) {
+	for _, suite := range []Ciphersuite{CiphersuiteProofOfPossession, CiphersuiteMessageAugmentation} {
+		params, err := SetUpWithCiphersuite(suite)
+		if err != nil {
+			t.Fatalf("SetUpWithCiphersuite failed: %v", err)
+		}
+		const size = 13
+		message := []byte("slot 42 head")
+		committee, signatures := generateCommittee(t, *params, size, message)
+		aggregator, err := NewAggregator(*params, committee, message)
+		if err != nil {
+			t.Fatalf("NewAggregator failed: %v", err)
+		}
+
+		// 成员3与成员7不参与,其余成员的签名各提交两次
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		added := 0
+		for round := 0; round < 2; round++ {
+			for i := 0; i < size; i++ {
+				if i == 3 || i == 7 {
+					continue
+				}
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					ok, err := aggregator.Add(i, signatures[i])
+					if err != nil {
+						t.Errorf("Add(%d) failed: %v", i, err)
+						return
+					}
+					if ok {
+						mu.Lock()
+						added++
+						mu.Unlock()
+					}
+				}(i)
+			}
+		}
+		wg.Wait()
+		if added != size-2 || aggregator.Count() != size-2 {
+			t.Fatalf("%v: %d signatures added, count %d, expected %d", suite, added, aggregator.Count(), size-2)
+		}
+		if aggregator.Has(3) || !aggregator.Has(4) {
+			t.Fatalf("%v: unexpected participants", suite)
+		}
+
+		attestation, err := aggregator.Aggregate()
+		if err != nil {
+			t.Fatalf("Aggregate failed: %v", err)
+		}
+		isValid, err := VerifyAggregateAttestation(*params, committee, *attestation)
+		if err != nil || !isValid {
+			t.Fatalf("%v: VerifyAggregateAttestation failed: %v", suite, err)
+		}
+
+		// 序列化后仍然可以验证
+		decoded, err := UnmarshalAggregateAttestation(*params, MarshalAggregateAttestation(*attestation))
+		if err != nil {
+			t.Fatalf("UnmarshalAggregateAttestation failed: %v", err)
+		}
+		if !bytes.Equal(decoded.Message, message) || !bytes.Equal(decoded.Participants, attestation.Participants) {
+			t.Fatalf("%v: decoded attestation differs", suite)
+		}
+		isValid, err = VerifyAggregateAttestation(*params, committee, *decoded)
+		if err != nil || !isValid {
+			t.Fatalf("%v: VerifyAggregateAttestation failed on decoded attestation: %v", suite, err)
+		}
+
+		// 位图中多标记一个未参与的成员
+		attestation.Participants.Set(3)
+		isValid, err = VerifyAggregateAttestation(*params, committee, *attestation)
+		if err != nil || isValid {
+			t.Fatalf("%v: VerifyAggregateAttestation accepted a wrong bitfield", suite)
+		}
+	}
+}
+
+// TestAggregatorInvalid 测试无效签名、越界索引、不支持的方案与无效的编码。
+func TestAggregatorInvalid(t *testing.T) {
+	params, err := SetUpWithCiphersuite(CiphersuiteProofOfPossession)
+	if err != nil {
+		t.Fatalf("SetUpWithCiphersuite failed: %v", err)
+	}
+	message := []byte("slot 43 head")
+	committee, signatures := generateCommittee(t, *params, 10, message)
+	aggregator, err := NewAggregator(*params, committee, message)
+	if err != nil {
+		t.Fatalf("NewAggregator failed: %v", err)
+	}
+	if _, err := aggregator.Aggregate(); err == nil {
+		t.Fatal("Aggregate was expected to fail without signatures")
+	}
+	if _, err := aggregator.Add(0, signatures[1]); err == nil {
+		t.Fatal("Add accepted a signature from another member")
+	}
+	if _, err := aggregator.Add(10, signatures[0]); err == nil {
+		t.Fatal("Add accepted an out-of-range index")
+	}
+	if _, err := aggregator.Add(0, NewG2Point(bn254.G2Affine{})); !errors.Is(err, ErrPointAtInfinity) {
+		t.Fatalf("Add returned %v, expected ErrPointAtInfinity", err)
+	}
+	if _, err := aggregator.AddSignature(0, BLSSignature{Message: []byte("other"), Signature: signatures[0]}); err == nil {
+		t.Fatal("AddSignature accepted another message")
+	}
+	if aggregator.Count() != 0 {
+		t.Fatal("invalid signatures were aggregated")
+	}
+	if ok, err := aggregator.Add(9, signatures[9]); err != nil || !ok {
+		t.Fatalf("Add failed: %v", err)
+	}
+	attestation, err := aggregator.Aggregate()
+	if err != nil {
+		t.Fatalf("Aggregate failed: %v", err)
+	}
+
+	// 位图长度与委员会大小不一致,或者填充位不为0
+	if _, err := VerifyAggregateAttestation(*params, committee[:8], *attestation); err == nil {
+		t.Fatal("VerifyAggregateAttestation accepted a bitfield of the wrong length")
+	}
+	padded := *attestation
+	padded.Participants = append(Bitfield{}, attestation.Participants...)
+	padded.Participants.Set(12)
+	if _, err := VerifyAggregateAttestation(*params, committee, padded); err == nil {
+		t.Fatal("VerifyAggregateAttestation accepted bits beyond the committee size")
+	}
+
+	encoded := MarshalAggregateAttestation(*attestation)
+	if _, err := UnmarshalAggregateAttestation(*params, encoded[:len(encoded)-1]); !errors.Is(err, ErrInvalidLength) {
+		t.Fatalf("UnmarshalAggregateAttestation returned %v, expected ErrInvalidLength", err)
+	}
+	if _, err := UnmarshalAggregateAttestation(*params, encoded[:6]); !errors.Is(err, ErrInvalidLength) {
+		t.Fatalf("UnmarshalAggregateAttestation returned %v, expected ErrInvalidLength", err)
+	}
+
+	basicParams, err := SetUpWithCiphersuite(CiphersuiteBasic)
+	if err != nil {
+		t.Fatalf("SetUpWithCiphersuite failed: %v", err)
+	}
+	if _, err := NewAggregator(*basicParams, committee, message); err == nil {
+		t.Fatal("NewAggregator was expected to reject BASIC params")
+	}
+
+	// 原有参数没有持有性证明的约束,不能用于同消息聚合
+	noneParams, err := SetUp()
+	if err != nil {
+		t.Fatalf("SetUp failed: %v", err)
+	}
+	if _, err := NewAggregator(*noneParams, committee, message); err == nil {
+		t.Fatal("NewAggregator was expected to reject params without a ciphersuite")
+	}
+	if _, err := VerifyAggregateAttestation(*noneParams, committee, *attestation); err == nil {
+		t.Fatal("VerifyAggregateAttestation was expected to reject params without a ciphersuite")
+	}
+}
@@ -0,0 +1,171 @@
+package bls
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+)
+
+// batchRandomizerBits 为批量验证中随机小指数的比特数。
+// 一个无效签名通过批量验证的概率不超过2^-64。
+const batchRandomizerBits = 64
+
+// BatchVerify 批量验证多个相互独立的(公钥, 签名)对,publicKeys[i]对应signatures[i]。
+// 对每一项选择随机小指数r_i,将全部验证合并为一次多重配对:
+// e(r_1*pk_1, h(m_1)) * ... * e(r_n*pk_n, h(m_n)) * e(G1Generator, -(r_1*sigma_1 + ... + r_n*sigma_n)) =?= 1
+// 与逐个调用Verify相比,n个签名只需n+1次Miller loop和一次最终幂运算。
+//
+// 批量验证失败时,通过二分法找出所有无效签名的下标。
+// 公钥或签名未通过ValidatePublicKey/ValidateSignature检查的项同样计入无效签名的下标。
+// workers大于1时,签名被划分为workers组,在多个goroutine中并行验证。
+//
+// 返回值:
+//   - bool: 所有签名是否都有效
+//   - []int: 无效签名的下标(升序),全部有效时为nil
+//   - error: 如果参数不合法或验证过程失败,返回错误信息
+func BatchVerify(blsParams BLSParams, publicKeys []G1Point, signatures []BLSSignature, workers int) (bool, []int, error) {
+	backend, err := backendOf(blsParams, VariantMinPublicKeySize)
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to batch verify signatures: %v", err)
+	}
+	if len(publicKeys) != len(signatures) {
+		return false, nil, fmt.Errorf("failed to batch verify signatures: %d public keys but %d signatures", len(publicKeys), len(signatures))
+	}
+	if len(signatures) == 0 {
+		return true, nil, nil
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(signatures) {
+		workers = len(signatures)
+	}
+
+	// 每一组的下标范围
+	chunkSize := (len(signatures) + workers - 1) / workers
+	var chunks [][]int
+	for start := 0; start < len(signatures); start += chunkSize {
+		end := start + chunkSize
+		if end > len(signatures) {
+			end = len(signatures)
+		}
+		indices := make([]int, 0, end-start)
+		for i := start; i < end; i++ {
+			indices = append(indices, i)
+		}
+		chunks = append(chunks, indices)
+	}
+
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	var invalid []int
+	var firstErr error
+	for _, chunk := range chunks {
+		wg.Add(1)
+		go func(indices []int) {
+			defer wg.Done()
+			bad, err := batchFindInvalid(backend, blsParams, publicKeys, signatures, indices)
+			mutex.Lock()
+			defer mutex.Unlock()
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+			invalid = append(invalid, bad...)
+		}(chunk)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return false, nil, fmt.Errorf("failed to batch verify signatures: %v", firstErr)
+	}
+	if len(invalid) == 0 {
+		return true, nil, nil
+	}
+	sort.Ints(invalid)
+	return false, invalid, nil
+}
+
+// batchFindInvalid 对indices中的签名先进行整体批量验证,失败时二分查找无效签名。
+// 公钥或签名未通过有效性检查的签名直接计为无效,不参与配对检查:
+// 随机小指数只在素数阶子群中保证批量验证的可靠性。
+func batchFindInvalid(backend curveBackend, blsParams BLSParams, publicKeys []G1Point, signatures []BLSSignature, indices []int) ([]int, error) {
+	var invalid []int
+	checked := make([]int, 0, len(indices))
+	for _, i := range indices {
+		if ValidatePublicKey(blsParams, publicKeys[i]) != nil || ValidateSignature(blsParams, signatures[i].Signature) != nil {
+			invalid = append(invalid, i)
+			continue
+		}
+		checked = append(checked, i)
+	}
+	if len(checked) == 0 {
+		return invalid, nil
+	}
+
+	// h(m_i)只计算一次,二分查找时复用
+	hms := make(map[int]point, len(checked))
+	for _, i := range checked {
+		hm, err := backend.g2().hashToCurve(augmentMessage(blsParams, publicKeys[i], signatures[i].Message), blsParams.DST)
+		if err != nil {
+			return nil, err
+		}
+		hms[i] = hm
+	}
+	bad, err := batchBisect(backend, blsParams, publicKeys, signatures, hms, checked)
+	if err != nil {
+		return nil, err
+	}
+	return append(invalid, bad...), nil
+}
+
+func batchBisect(backend curveBackend, blsParams BLSParams, publicKeys []G1Point, signatures []BLSSignature, hms map[int]point, indices []int) ([]int, error) {
+	isValid, err := batchCheck(backend, blsParams, publicKeys, signatures, hms, indices)
+	if err != nil {
+		return nil, err
+	}
+	if isValid {
+		return nil, nil
+	}
+	if len(indices) == 1 {
+		return indices, nil
+	}
+	middle := len(indices) / 2
+	left, err := batchBisect(backend, blsParams, publicKeys, signatures, hms, indices[:middle])
+	if err != nil {
+		return nil, err
+	}
+	right, err := batchBisect(backend, blsParams, publicKeys, signatures, hms, indices[middle:])
+	if err != nil {
+		return nil, err
+	}
+	return append(left, right...), nil
+}
+
+// batchCheck 使用新的随机小指数对indices中的签名执行一次多重配对检查。
+func batchCheck(backend curveBackend, blsParams BLSParams, publicKeys []G1Point, signatures []BLSSignature, hms map[int]point, indices []int) (bool, error) {
+	g1s := make([]point, 0, len(indices)+1)
+	g2s := make([]point, 0, len(indices)+1)
+	bound := new(big.Int).Lsh(big.NewInt(1), batchRandomizerBits)
+
+	aggregate := backend.g2().infinity()
+	for _, i := range indices {
+		// r_i <- [1, 2^64)
+		r, err := rand.Int(rand.Reader, bound)
+		if err != nil {
+			return false, err
+		}
+		if r.Sign() == 0 {
+			r.SetInt64(1)
+		}
+		aggregate = aggregate.add(signatures[i].Signature.p.mul(r))
+
+		g1s = append(g1s, publicKeys[i].p.mul(r))
+		g2s = append(g2s, hms[i])
+	}
+
+	g1s = append(g1s, blsParams.G1Generator.p)
+	g2s = append(g2s, aggregate.neg())
+	return backend.pairingCheck(g1s, g2s)
+}
@@ -0,0 +1,94 @@
+package bls
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func generateBatch(t testing.TB, params *BLSParams, n int) ([]G1Point, []BLSSignature) {
+	publicKeys := make([]G1Point, n)
+	signatures := make([]BLSSignature, n)
+	for i := 0; i < n; i++ {
+		keyPair, err := KeyGeneration(*params)
+		if err != nil {
+			t.Fatalf("KeyGeneration failed: %v", err)
+		}
+		signature, err := Sign(*params, keyPair.PrivateKey, []byte(fmt.Sprintf("transaction %d", i)))
+		if err != nil {
+			t.Fatalf("Sign failed: %v", err)
+		}
+		publicKeys[i] = keyPair.PublicKey
+		signatures[i] = *signature
+	}
+	return publicKeys, signatures
+}
+
+// TestBatchVerify 测试批量验证全部有效的签名。
+func TestBatchVerify(t *testing.T) {
+	params, err := SetUp()
+	if err != nil {
+		t.Fatalf("SetUp failed: %v", err)
+	}
+	publicKeys, signatures := generateBatch(t, params, 16)
+
+	for _, workers := range []int{1, 4} {
+		isValid, invalid, err := BatchVerify(*params, publicKeys, signatures, workers)
+		if err != nil {
+			t.Fatalf("BatchVerify failed: %v", err)
+		}
+		if !isValid || invalid != nil {
+			t.Fatalf("batch was expected to be valid, invalid indices: %v", invalid)
+		}
+	}
+}
+
+// TestBatchVerifyFindsInvalid 测试批量验证失败时能找出所有无效签名。
+func TestBatchVerifyFindsInvalid(t *testing.T) {
+	params, err := SetUp()
+	if err != nil {
+		t.Fatalf("SetUp failed: %v", err)
+	}
+	publicKeys, signatures := generateBatch(t, params, 16)
+
+	// 篡改第3个签名的消息,交换第10和第11个签名
+	signatures[3].Message = []byte("forged")
+	signatures[10].Signature, signatures[11].Signature = signatures[11].Signature, signatures[10].Signature
+
+	for _, workers := range []int{1, 3} {
+		isValid, invalid, err := BatchVerify(*params, publicKeys, signatures, workers)
+		if err != nil {
+			t.Fatalf("BatchVerify failed: %v", err)
+		}
+		if isValid {
+			t.Fatal("batch was expected to be invalid")
+		}
+		if !reflect.DeepEqual(invalid, []int{3, 10, 11}) {
+			t.Fatalf("unexpected invalid indices: %v", invalid)
+		}
+	}
+}
+
+func BenchmarkVerify(b *testing.B) {
+	params, _ := SetUp()
+	publicKeys, signatures := generateBatch(b, params, 64)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for i := range signatures {
+			if _, err := Verify(*params, publicKeys[i], signatures[i]); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkBatchVerify(b *testing.B) {
+	params, _ := SetUp()
+	publicKeys, signatures := generateBatch(b, params, 64)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		if _, _, err := BatchVerify(*params, publicKeys, signatures, 4); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
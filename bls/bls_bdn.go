@@ -0,0 +1,143 @@
+package bls
+
+// 参考论文:
+// Dan Boneh, Manu Drijvers and Gregory Neven.
+// "Compact Multi-Signatures for Smaller Blockchains." ASIACRYPT 2018.
+//
+// 论文链接: https://eprint.iacr.org/2018/483
+//
+// BDN多重签名不需要持有性证明也能抵御rogue-key攻击。对签名者公钥集合PK = (pk_1, ..., pk_n):
+//   - 系数: a_i = H_1(pk_i || SHA-256(pk_1 || ... || pk_n)),H_1为hash-to-field(BN254上与fr.Hash相同)
+//   - 聚合公钥: apk = pk_1^{a_1} * ... * pk_n^{a_n}
+//   - 聚合签名: sigma = sigma_1^{a_1} * ... * sigma_n^{a_n},sigma_i为对同一消息m的普通BLS签名
+//   - 验证: e(apk, h(m)) =?= e(G1Generator, sigma)
+//
+// 系数依赖于整个有序的公钥列表,签名者与验证者必须使用相同顺序的公钥列表。
+// MESSAGE-AUGMENTATION方案下每个签名者签的消息不同,不支持BDN多重签名。
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"github.com/consensys/gnark-crypto/field/hash"
+	"math/big"
+)
+
+// bdnCoefficientDST 返回计算系数时hash-to-field使用的DST,例如BN254上为"BLS_BDN_BN254_XMD:SHA-256_COEFFICIENTS_"。
+func bdnCoefficientDST(backend curveBackend) []byte {
+	return []byte("BLS_BDN_" + backend.name() + "_XMD:SHA-256_COEFFICIENTS_")
+}
+
+// BDNCoefficients 计算公钥列表中每个公钥的系数a_i,公钥必须通过KeyValidate检查。
+func BDNCoefficients(blsParams BLSParams, publicKeys []G1Point) ([]*big.Int, error) {
+	backend, err := backendOf(blsParams, VariantMinPublicKeySize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute coefficients: %v", err)
+	}
+	return bdnCoefficients(backend, publicKeys)
+}
+
+// BDNAggregatePublicKeys 计算聚合公钥apk = prod pk_i^{a_i}。
+func BDNAggregatePublicKeys(blsParams BLSParams, publicKeys []G1Point) (*G1Point, error) {
+	backend, err := checkBDNParams(blsParams)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate public keys: %v", err)
+	}
+	coefficients, err := bdnCoefficients(backend, publicKeys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate public keys: %v", err)
+	}
+	points := make([]point, len(publicKeys))
+	for i := range publicKeys {
+		points[i] = publicKeys[i].p
+	}
+	return &G1Point{linearCombination(backend.g1(), points, coefficients)}, nil
+}
+
+// BDNAggregateSignatures 将签名者对同一消息的签名加权聚合: sigma = prod sigma_i^{a_i}。
+// signatures[i]必须是publicKeys[i]对应私钥的签名。
+func BDNAggregateSignatures(blsParams BLSParams, publicKeys []G1Point, signatures []BLSSignature) (*BLSSignature, error) {
+	backend, err := checkBDNParams(blsParams)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate signatures: %v", err)
+	}
+	if len(signatures) != len(publicKeys) {
+		return nil, fmt.Errorf("failed to aggregate signatures: %d public keys but %d signatures", len(publicKeys), len(signatures))
+	}
+	coefficients, err := bdnCoefficients(backend, publicKeys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate signatures: %v", err)
+	}
+	points := make([]point, len(signatures))
+	for i, signature := range signatures {
+		if string(signature.Message) != string(signatures[0].Message) {
+			return nil, fmt.Errorf("failed to aggregate signatures: signatures are on different messages")
+		}
+		if err := ValidateSignature(blsParams, signature.Signature); err != nil {
+			return nil, fmt.Errorf("failed to aggregate signatures: signature %d: %w", i, err)
+		}
+		points[i] = signature.Signature.p
+	}
+	return &BLSSignature{
+		Message:   signatures[0].Message,
+		Signature: G2Point{linearCombination(backend.g2(), points, coefficients)},
+	}, nil
+}
+
+// VerifyMulti 验证BDN多重签名: e(apk, h(m)) =?= e(G1Generator, sigma),apk由publicKeys计算。
+func VerifyMulti(blsParams BLSParams, publicKeys []G1Point, multiSignature BLSSignature) (bool, error) {
+	aggregatePublicKey, err := BDNAggregatePublicKeys(blsParams, publicKeys)
+	if err != nil {
+		return false, fmt.Errorf("failed to verify multi-signature: %v", err)
+	}
+	return Verify(blsParams, *aggregatePublicKey, multiSignature)
+}
+
+// bdnCoefficients 计算a_i = H_1(pk_i || SHA-256(pk_1 || ... || pk_n)),
+// H_1为expand_message_xmd输出L = ceil(log2(r) / 8) + 16字节后模r,与gnark-crypto的fr.Hash相同。
+func bdnCoefficients(backend curveBackend, publicKeys []G1Point) ([]*big.Int, error) {
+	if len(publicKeys) == 0 {
+		return nil, fmt.Errorf("empty public key set")
+	}
+	// 公钥集合的摘要: SHA-256(pk_1 || ... || pk_n)
+	h := sha256.New()
+	for i := range publicKeys {
+		if err := validatePoint(backend.curve(), publicKeys[i].p); err != nil {
+			return nil, fmt.Errorf("invalid public key %d: %v", i, err)
+		}
+		h.Write(publicKeys[i].Bytes())
+	}
+	setDigest := h.Sum(nil)
+
+	r := backend.curve().ScalarField()
+	length := (r.BitLen()+7)/8 + 16
+	coefficients := make([]*big.Int, len(publicKeys))
+	for i := range publicKeys {
+		uniformBytes, err := hash.ExpandMsgXmd(append(publicKeys[i].Bytes(), setDigest...), bdnCoefficientDST(backend), length)
+		if err != nil {
+			return nil, err
+		}
+		coefficients[i] = new(big.Int).SetBytes(uniformBytes)
+		coefficients[i].Mod(coefficients[i], r)
+	}
+	return coefficients, nil
+}
+
+// linearCombination 计算points[0]^{scalars[0]} * ... * points[n-1]^{scalars[n-1]}。
+func linearCombination(g group, points []point, scalars []*big.Int) point {
+	result := g.infinity()
+	for i := range points {
+		result = result.add(points[i].mul(scalars[i]))
+	}
+	return result
+}
+
+func checkBDNParams(blsParams BLSParams) (curveBackend, error) {
+	backend, err := backendOf(blsParams, VariantMinPublicKeySize)
+	if err != nil {
+		return nil, err
+	}
+	if blsParams.Ciphersuite == CiphersuiteMessageAugmentation {
+		return nil, fmt.Errorf("ciphersuite %v does not support multi-signatures", blsParams.Ciphersuite)
+	}
+	return backend, nil
+}
@@ -0,0 +1,172 @@
+package bls
+
+import (
+	"crypto/sha256"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"math/big"
+	"testing"
+)
+
+// TestBDNMultiSignature 测试BDN多重签名的聚合与验证。
+func TestBDNMultiSignature(t *testing.T) {
+	params, err := SetUpWithCiphersuite(CiphersuiteBasic)
+	if err != nil {
+		t.Fatalf("SetUpWithCiphersuite failed: %v", err)
+	}
+	// 签名者数量超过单次hash-to-field的输出上限
+	const n = 200
+	message := []byte("multi-signed block")
+	publicKeys := make([]G1Point, n)
+	signatures := make([]BLSSignature, n)
+	for i := range publicKeys {
+		keyPair, err := KeyGeneration(*params)
+		if err != nil {
+			t.Fatalf("KeyGeneration failed: %v", err)
+		}
+		publicKeys[i] = keyPair.PublicKey
+		signature, err := Sign(*params, keyPair.PrivateKey, message)
+		if err != nil {
+			t.Fatalf("Sign failed: %v", err)
+		}
+		signatures[i] = *signature
+	}
+
+	multiSignature, err := BDNAggregateSignatures(*params, publicKeys, signatures)
+	if err != nil {
+		t.Fatalf("BDNAggregateSignatures failed: %v", err)
+	}
+	isValid, err := VerifyMulti(*params, publicKeys, *multiSignature)
+	if err != nil || !isValid {
+		t.Fatalf("VerifyMulti failed: %v", err)
+	}
+
+	// 系数依赖于公钥列表的顺序
+	publicKeys[0], publicKeys[1] = publicKeys[1], publicKeys[0]
+	isValid, err = VerifyMulti(*params, publicKeys, *multiSignature)
+	if err != nil || isValid {
+		t.Fatal("VerifyMulti accepted a reordered public key list")
+	}
+	// 缺少一个签名者
+	isValid, err = VerifyMulti(*params, publicKeys[1:], *multiSignature)
+	if err != nil || isValid {
+		t.Fatal("VerifyMulti accepted a smaller signer set")
+	}
+
+	// 签名不是针对同一消息
+	signatures[1].Message = []byte("other")
+	if _, err := BDNAggregateSignatures(*params, publicKeys, signatures); err == nil {
+		t.Fatal("BDNAggregateSignatures was expected to reject different messages")
+	}
+}
+
+// TestBDNRogueKey 测试rogue-key攻击: 攻击者选择pk_a = g1^s - pk_honest,
+// 朴素聚合下h(m)^s即可作为双方的多重签名,而BDN加权聚合下伪造失败。
+func TestBDNRogueKey(t *testing.T) {
+	params, err := SetUp()
+	if err != nil {
+		t.Fatalf("SetUp failed: %v", err)
+	}
+	honest, err := KeyGeneration(*params)
+	if err != nil {
+		t.Fatalf("KeyGeneration failed: %v", err)
+	}
+	s := big.NewInt(123456789)
+	rogue := G1Point{params.G1Generator.p.mul(s).sub(honest.PublicKey.p)}
+	publicKeys := []G1Point{honest.PublicKey, rogue}
+
+	message := []byte("honest never signed this")
+	forged, err := Sign(*params, s, message)
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+	isValid, err := FastAggregateVerify(*params, publicKeys, message, forged.Signature)
+	if err != nil || !isValid {
+		t.Fatal("the rogue-key forgery was expected to pass naive aggregation")
+	}
+	isValid, err = VerifyMulti(*params, publicKeys, *forged)
+	if err != nil || isValid {
+		t.Fatal("VerifyMulti accepted a rogue-key forgery")
+	}
+
+	coefficients, err := BDNCoefficients(*params, publicKeys)
+	if err != nil {
+		t.Fatalf("BDNCoefficients failed: %v", err)
+	}
+	if len(coefficients) != 2 || coefficients[0].Cmp(coefficients[1]) == 0 {
+		t.Fatal("unexpected coefficients")
+	}
+	if _, err := BDNCoefficients(*params, []G1Point{NewG1Point(bn254.G1Affine{})}); err == nil {
+		t.Fatal("BDNCoefficients was expected to reject the point at infinity")
+	}
+}
+
+// TestBDNCoefficientsBN254 测试BN254上的系数与fr.Hash的hash-to-field结果一致,保证改为通用实现后系数不变。
+func TestBDNCoefficientsBN254(t *testing.T) {
+	params, err := SetUp()
+	if err != nil {
+		t.Fatalf("SetUp failed: %v", err)
+	}
+	publicKeys := make([]G1Point, 3)
+	h := sha256.New()
+	for i := range publicKeys {
+		keyPair, err := KeyGeneration(*params)
+		if err != nil {
+			t.Fatalf("KeyGeneration failed: %v", err)
+		}
+		publicKeys[i] = keyPair.PublicKey
+		h.Write(keyPair.PublicKey.Bytes())
+	}
+	setDigest := h.Sum(nil)
+
+	coefficients, err := BDNCoefficients(*params, publicKeys)
+	if err != nil {
+		t.Fatalf("BDNCoefficients failed: %v", err)
+	}
+	for i := range publicKeys {
+		expected, err := fr.Hash(append(publicKeys[i].Bytes(), setDigest...), []byte("BLS_BDN_BN254_XMD:SHA-256_COEFFICIENTS_"), 1)
+		if err != nil {
+			t.Fatalf("fr.Hash failed: %v", err)
+		}
+		if coefficients[i].Cmp(expected[0].BigInt(new(big.Int))) != 0 {
+			t.Fatalf("coefficient %d does not match fr.Hash", i)
+		}
+	}
+}
+
+// TestBDNOnCurves 测试所有曲线上的BDN多重签名。
+func TestBDNOnCurves(t *testing.T) {
+	message := []byte("multi-signed block")
+	for _, curve := range SupportedCurves() {
+		params, err := SetUpOnCurveWithCiphersuite(curve, CiphersuiteProofOfPossession)
+		if err != nil {
+			t.Fatalf("%v: SetUpOnCurveWithCiphersuite failed: %v", curve, err)
+		}
+		publicKeys := make([]G1Point, 3)
+		signatures := make([]BLSSignature, 3)
+		for i := range publicKeys {
+			keyPair, err := KeyGeneration(*params)
+			if err != nil {
+				t.Fatalf("%v: KeyGeneration failed: %v", curve, err)
+			}
+			signature, err := Sign(*params, keyPair.PrivateKey, message)
+			if err != nil {
+				t.Fatalf("%v: Sign failed: %v", curve, err)
+			}
+			publicKeys[i] = keyPair.PublicKey
+			signatures[i] = *signature
+		}
+		multiSignature, err := BDNAggregateSignatures(*params, publicKeys, signatures)
+		if err != nil {
+			t.Fatalf("%v: BDNAggregateSignatures failed: %v", curve, err)
+		}
+		isValid, err := VerifyMulti(*params, publicKeys, *multiSignature)
+		if err != nil || !isValid {
+			t.Fatalf("%v: VerifyMulti failed: %v", curve, err)
+		}
+		isValid, err = VerifyMulti(*params, publicKeys[1:], *multiSignature)
+		if err != nil || isValid {
+			t.Fatalf("%v: VerifyMulti accepted a multi-signature for a different signer set", curve)
+		}
+	}
+}
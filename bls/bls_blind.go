@@ -0,0 +1,127 @@
+package bls
+
+// 参考文献:
+// A. Boldyreva. "Threshold Signatures, Multisignatures and Blind Signatures Based on the
+// Gap-Diffie-Hellman-Group Signature Scheme." PKC 2003.
+//
+// 盲签名协议:
+//   - 用户: 随机选取r,计算盲化消息M' = h(m)^r,将M'发送给签名者
+//   - 签名者: 计算盲签名σ' = M'^x,签名者看不到消息m
+//   - 用户: 验证e(pk, M') = e(g1, σ'),计算σ = σ'^(1/r) = h(m)^x
+//
+// 去盲后的σ是普通的BLS签名,可以通过Verify验证,且签名者无法将σ与某次签名请求关联。
+// MESSAGE-AUGMENTATION方案下h的输入为pk || m,因此盲化时需要签名者的公钥。
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+)
+
+// Blind 盲化消息,返回发送给签名者的盲化消息M' = h(m)^r和用户保存的盲化因子r。
+func Blind(blsParams BLSParams, publicKey G1Point, message []byte) (*G2Point, *big.Int, error) {
+	backend, err := backendOf(blsParams, VariantMinPublicKeySize)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to blind message: %v", err)
+	}
+	if err := ValidatePublicKey(blsParams, publicKey); err != nil {
+		return nil, nil, fmt.Errorf("failed to blind message: %w", err)
+	}
+	hm, err := backend.g2().hashToCurve(augmentMessage(blsParams, publicKey, message), blsParams.DST)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to blind message: %v", err)
+	}
+	r, err := randomNonZeroScalar(backend.curve().ScalarField())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to blind message: %v", err)
+	}
+	return &G2Point{hm.mul(r)}, r, nil
+}
+
+// BlindSign 对盲化消息签名: σ' = M'^x。
+// 盲化消息必须位于G2的素数阶子群中且不是无穷远点,否则签名可能泄露私钥的信息。
+func BlindSign(blsParams BLSParams, privateKey *big.Int, blindedMessage G2Point) (*G2Point, error) {
+	backend, err := backendOf(blsParams, VariantMinPublicKeySize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign blinded message: %v", err)
+	}
+	if err := checkPrivateKey(backend, privateKey); err != nil {
+		return nil, fmt.Errorf("failed to sign blinded message: %w", err)
+	}
+	if err := validatePoint(backend.curve(), blindedMessage.p); err != nil {
+		return nil, fmt.Errorf("failed to sign blinded message: %w", &ValidationError{Object: "blinded message", Err: err})
+	}
+	return &G2Point{blindedMessage.p.mul(privateKey)}, nil
+}
+
+// VerifyBlindSignature 验证盲签名: e(pk, M') = e(g1, σ')。
+// 公钥、盲化消息与盲签名都必须通过有效性检查,否则返回*ValidationError:
+// 盲化消息与盲签名同时为无穷远点时,配对等式对任意公钥都成立。
+func VerifyBlindSignature(blsParams BLSParams, publicKey G1Point, blindedMessage G2Point, blindSignature G2Point) (bool, error) {
+	backend, err := backendOf(blsParams, VariantMinPublicKeySize)
+	if err != nil {
+		return false, fmt.Errorf("failed to verify blind signature: %v", err)
+	}
+	if err := ValidatePublicKey(blsParams, publicKey); err != nil {
+		return false, fmt.Errorf("failed to verify blind signature: %w", err)
+	}
+	if err := validatePoint(backend.curve(), blindedMessage.p); err != nil {
+		return false, fmt.Errorf("failed to verify blind signature: %w", &ValidationError{Object: "blinded message", Err: err})
+	}
+	if err := validatePoint(backend.curve(), blindSignature.p); err != nil {
+		return false, fmt.Errorf("failed to verify blind signature: %w", &ValidationError{Object: "blind signature", Err: err})
+	}
+	isValid, err := backend.pairingCheck(
+		[]point{publicKey.p, blsParams.G1Generator.p},
+		[]point{blindedMessage.p, blindSignature.p.neg()},
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to verify blind signature: %v", err)
+	}
+	return isValid, nil
+}
+
+// Unblind 去盲: σ = σ'^(1/r),返回对message的普通BLS签名。
+// 去盲后会验证签名,签名者返回无效的盲签名时返回错误。
+func Unblind(blsParams BLSParams, publicKey G1Point, message []byte, blindSignature G2Point, blindingFactor *big.Int) (*BLSSignature, error) {
+	backend, err := backendOf(blsParams, VariantMinPublicKeySize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unblind signature: %v", err)
+	}
+	if blindingFactor == nil || blindingFactor.Sign() == 0 {
+		return nil, fmt.Errorf("failed to unblind signature: invalid blinding factor")
+	}
+	rInverse := new(big.Int).ModInverse(blindingFactor, backend.curve().ScalarField())
+	if rInverse == nil {
+		return nil, fmt.Errorf("failed to unblind signature: invalid blinding factor")
+	}
+	if err := validatePoint(backend.curve(), blindSignature.p); err != nil {
+		return nil, fmt.Errorf("failed to unblind signature: %w", &ValidationError{Object: "blind signature", Err: err})
+	}
+	blsSignature := &BLSSignature{
+		Message:   message,
+		Signature: G2Point{blindSignature.p.mul(rInverse)},
+	}
+
+	isValid, err := Verify(blsParams, publicKey, *blsSignature)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unblind signature: %v", err)
+	}
+	if !isValid {
+		return nil, fmt.Errorf("failed to unblind signature: invalid blind signature")
+	}
+	return blsSignature, nil
+}
+
+// randomNonZeroScalar 在[1, r)中均匀随机选取标量。
+func randomNonZeroScalar(r *big.Int) (*big.Int, error) {
+	for {
+		k, err := rand.Int(rand.Reader, r)
+		if err != nil {
+			return nil, err
+		}
+		if k.Sign() != 0 {
+			return k, nil
+		}
+	}
+}
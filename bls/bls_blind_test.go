@@ -0,0 +1,89 @@
+package bls
+
+import (
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"testing"
+)
+
+// TestBlindSignature 测试盲签名的完整流程,去盲后的签名可以通过Verify验证。
+func TestBlindSignature(t *testing.T) {
+	for _, suite := range []Ciphersuite{CiphersuiteBasic, CiphersuiteMessageAugmentation} {
+		params, err := SetUpWithCiphersuite(suite)
+		if err != nil {
+			t.Fatalf("SetUpWithCiphersuite failed: %v", err)
+		}
+		keyPair, err := KeyGeneration(*params)
+		if err != nil {
+			t.Fatalf("KeyGeneration failed: %v", err)
+		}
+		message := []byte("one-time token 42")
+
+		blindedMessage, blindingFactor, err := Blind(*params, keyPair.PublicKey, message)
+		if err != nil {
+			t.Fatalf("Blind failed: %v", err)
+		}
+		blindSignature, err := BlindSign(*params, keyPair.PrivateKey, *blindedMessage)
+		if err != nil {
+			t.Fatalf("BlindSign failed: %v", err)
+		}
+		isValid, err := VerifyBlindSignature(*params, keyPair.PublicKey, *blindedMessage, *blindSignature)
+		if err != nil || !isValid {
+			t.Fatalf("%v: VerifyBlindSignature failed: %v", suite, err)
+		}
+		signature, err := Unblind(*params, keyPair.PublicKey, message, *blindSignature, blindingFactor)
+		if err != nil {
+			t.Fatalf("%v: Unblind failed: %v", suite, err)
+		}
+		isValid, err = Verify(*params, keyPair.PublicKey, *signature)
+		if err != nil || !isValid {
+			t.Fatalf("%v: Verify failed: %v", suite, err)
+		}
+
+		// 去盲后的签名与直接签名相同,而盲签名与之不同
+		direct, err := Sign(*params, keyPair.PrivateKey, message)
+		if err != nil {
+			t.Fatalf("Sign failed: %v", err)
+		}
+		if !direct.Signature.Equal(signature.Signature) || direct.Signature.Equal(*blindSignature) {
+			t.Fatalf("%v: unexpected unblinded signature", suite)
+		}
+	}
+}
+
+// TestBlindSignatureInvalid 测试盲签名的错误处理。
+func TestBlindSignatureInvalid(t *testing.T) {
+	params, err := SetUp()
+	if err != nil {
+		t.Fatalf("SetUp failed: %v", err)
+	}
+	keyPair, err := KeyGeneration(*params)
+	if err != nil {
+		t.Fatalf("KeyGeneration failed: %v", err)
+	}
+	otherKeyPair, err := KeyGeneration(*params)
+	if err != nil {
+		t.Fatalf("KeyGeneration failed: %v", err)
+	}
+	message := []byte("token")
+	blindedMessage, blindingFactor, err := Blind(*params, keyPair.PublicKey, message)
+	if err != nil {
+		t.Fatalf("Blind failed: %v", err)
+	}
+
+	// 使用其他私钥产生的盲签名不能去盲
+	blindSignature, err := BlindSign(*params, otherKeyPair.PrivateKey, *blindedMessage)
+	if err != nil {
+		t.Fatalf("BlindSign failed: %v", err)
+	}
+	isValid, err := VerifyBlindSignature(*params, keyPair.PublicKey, *blindedMessage, *blindSignature)
+	if err != nil || isValid {
+		t.Fatal("VerifyBlindSignature accepted a signature under another key")
+	}
+	if _, err := Unblind(*params, keyPair.PublicKey, message, *blindSignature, blindingFactor); err == nil {
+		t.Fatal("Unblind was expected to reject a signature under another key")
+	}
+
+	if _, err := BlindSign(*params, keyPair.PrivateKey, NewG2Point(bn254.G2Affine{})); err == nil {
+		t.Fatal("BlindSign was expected to reject the point at infinity")
+	}
+}
@@ -0,0 +1,159 @@
+package bls
+
+// 参考规范:
+// D. Boneh, S. Gorbunov, R. Wahby, H. Wee, C. Wood, Z. Zhang.
+// "BLS Signatures." draft-irtf-cfrg-bls-signature-04.
+//
+// 规范链接: https://datatracker.ietf.org/doc/html/draft-irtf-cfrg-bls-signature-04
+//
+// 该文件提供规范中定义的三种签名方案(ciphersuite):
+//   - BASIC: 聚合验证要求消息互不相同
+//   - MESSAGE-AUGMENTATION: 签名前将公钥拼接在消息之前
+//   - PROOF-OF-POSSESSION: 公钥需附带持有性证明,允许同消息快速聚合验证
+//
+// 规范只给出了BLS12-381的ciphersuite,其他曲线按照规范的命名规则替换为各自的hash-to-curve套件,
+// 例如BN254为BN254G2_XMD:SHA-256_SVDW_RO_(公钥位于G1,签名位于G2),各曲线的套件见bls_curve.go。
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/mmsyan/GnarkPairingProject/utils"
+	"math/big"
+)
+
+// Ciphersuite 表示BLS签名所遵循的规范方案。
+// 零值CiphersuiteNone对应SetUp返回的原有参数,不声明遵循任何规范。
+type Ciphersuite int
+
+const (
+	CiphersuiteNone Ciphersuite = iota
+	CiphersuiteBasic
+	CiphersuiteMessageAugmentation
+	CiphersuiteProofOfPossession
+)
+
+const (
+	hashToCurveSuiteG1 = "BN254G1_XMD:SHA-256_SVDW_RO_"
+	hashToCurveSuiteG2 = "BN254G2_XMD:SHA-256_SVDW_RO_"
+	prehashTag         = "PREHASH_"
+	keyGenSalt         = "BLS-SIG-KEYGEN-SALT-"
+)
+
+// String 返回ciphersuite的名称。
+func (suite Ciphersuite) String() string {
+	switch suite {
+	case CiphersuiteNone:
+		return "NONE"
+	case CiphersuiteBasic:
+		return "BASIC"
+	case CiphersuiteMessageAugmentation:
+		return "MESSAGE-AUGMENTATION"
+	case CiphersuiteProofOfPossession:
+		return "PROOF-OF-POSSESSION"
+	default:
+		return fmt.Sprintf("Ciphersuite(%d)", int(suite))
+	}
+}
+
+// ID 返回BN254上ciphersuite的标识符,同时作为签名时hash-to-curve的DST。
+// 例如BASIC方案为"BLS_SIG_BN254G2_XMD:SHA-256_SVDW_RO_NUL_"。
+func (suite Ciphersuite) ID() (string, error) {
+	return suite.id(hashToCurveSuiteG2)
+}
+
+// MinSigID 返回BN254上签名位于G1的变体下ciphersuite的标识符,
+// 例如BASIC方案为"BLS_SIG_BN254G1_XMD:SHA-256_SVDW_RO_NUL_"。
+func (suite Ciphersuite) MinSigID() (string, error) {
+	return suite.id(hashToCurveSuiteG1)
+}
+
+func (suite Ciphersuite) id(hashToCurveSuite string) (string, error) {
+	switch suite {
+	case CiphersuiteBasic:
+		return "BLS_SIG_" + hashToCurveSuite + "NUL_", nil
+	case CiphersuiteMessageAugmentation:
+		return "BLS_SIG_" + hashToCurveSuite + "AUG_", nil
+	case CiphersuiteProofOfPossession:
+		return "BLS_SIG_" + hashToCurveSuite + "POP_", nil
+	default:
+		return "", fmt.Errorf("unsupported ciphersuite: %v", suite)
+	}
+}
+
+// SetUpWithCiphersuite 按照指定的规范方案初始化BN254上的BLS参数,其他曲线使用SetUpOnCurveWithCiphersuite。
+// 签名DST为ciphersuite的标识符,持有性证明的DST为"BLS_POP_BN254G2_XMD:SHA-256_SVDW_RO_POP_",
+// 先哈希后签名的DST为ciphersuite的标识符后加"PREHASH_"。
+func SetUpWithCiphersuite(suite Ciphersuite) (*BLSParams, error) {
+	return SetUpOnCurveWithCiphersuite(ecc.BN254, suite)
+}
+
+// KeyGen 按照draft-04第2.3节在参数的曲线上由输入密钥材料IKM确定性地派生私钥,与EIP-2333的HKDF_mod_r相同。
+// IKM至少为32字节且必须保密,keyInfo为可选的上下文信息(可以为空)。
+// 注意draft-05第一次Extract直接使用salt,只在SK为0重试时才计算H(salt),两者的输出不同。
+//
+//	salt = "BLS-SIG-KEYGEN-SALT-"
+//	SK = 0
+//	while SK == 0:
+//	    salt = H(salt)
+//	    PRK = HKDF-Extract(salt, IKM || I2OSP(0, 1))
+//	    OKM = HKDF-Expand(PRK, keyInfo || I2OSP(L, 2), L)
+//	    SK = OS2IP(OKM) mod r
+func KeyGen(blsParams BLSParams, ikm []byte, keyInfo []byte) (*big.Int, error) {
+	backend, err := backendFor(curveOf(blsParams))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate key: %v", err)
+	}
+	if len(ikm) < 32 {
+		return nil, fmt.Errorf("failed to generate key: IKM must be at least 32 bytes")
+	}
+	return hkdfModR(ikm, keyInfo, backend.curve().ScalarField())
+}
+
+// hkdfModR 实现KeyGen中的HKDF_mod_r过程,不检查IKM的长度。
+// r为标量域的阶,输出长度L = ceil((3 * ceil(log2(r))) / 16),BN254、BLS12-381和BLS12-377均为48字节。
+func hkdfModR(ikm []byte, keyInfo []byte, r *big.Int) (*big.Int, error) {
+	keyGenLength := (3*r.BitLen() + 15) / 16
+	ikmWithZero := append(append([]byte{}, ikm...), 0)
+	info := append(append([]byte{}, keyInfo...), byte(keyGenLength>>8), byte(keyGenLength))
+
+	salt := []byte(keyGenSalt)
+	sk := new(big.Int)
+	for sk.Sign() == 0 {
+		digest := sha256.Sum256(salt)
+		salt = digest[:]
+		prk := utils.HKDFExtract(salt, ikmWithZero)
+		okm, err := utils.HKDFExpand(prk, info, keyGenLength)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate key: %v", err)
+		}
+		sk.SetBytes(okm)
+		sk.Mod(sk, r)
+	}
+	return sk, nil
+}
+
+// SkToPk 由私钥计算公钥: pk = G1Generator^sk
+func SkToPk(blsParams BLSParams, privateKey *big.Int) G1Point {
+	if blsParams.G1Generator.p == nil {
+		return G1Point{}
+	}
+	return G1Point{blsParams.G1Generator.p.mul(privateKey)}
+}
+
+// KeyValidate 按照规范检查公钥是否有效: 公钥必须在参数曲线的G1上、位于素数阶子群中且不是无穷远点。
+func KeyValidate(blsParams BLSParams, publicKey G1Point) bool {
+	return ValidatePublicKey(blsParams, publicKey) == nil
+}
+
+// augmentMessage 在MESSAGE-AUGMENTATION方案下将压缩公钥拼接在消息之前,其他方案下原样返回消息。
+func augmentMessage(blsParams BLSParams, publicKey G1Point, message []byte) []byte {
+	return augmentMessageBytes(blsParams, publicKey.Bytes(), message)
+}
+
+func augmentMessageBytes(blsParams BLSParams, publicKeyBytes []byte, message []byte) []byte {
+	if blsParams.Ciphersuite != CiphersuiteMessageAugmentation {
+		return message
+	}
+	return append(append([]byte{}, publicKeyBytes...), message...)
+}
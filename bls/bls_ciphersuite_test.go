@@ -0,0 +1,200 @@
+package bls
+
+import (
+	"encoding/hex"
+	"fmt"
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"math/big"
+	"testing"
+)
+
+// TestKeyGenKnownAnswer 测试KeyGen的已知答案。
+// 规范没有给出BN254的测试向量,期望值由独立的Python实现按规范第2.3节计算得到。
+func TestKeyGenKnownAnswer(t *testing.T) {
+	params, err := SetUp()
+	if err != nil {
+		t.Fatalf("SetUp failed: %v", err)
+	}
+	ikm := make([]byte, 32)
+	for i := range ikm {
+		ikm[i] = byte(i)
+	}
+	vectors := []struct {
+		keyInfo  []byte
+		expected string
+	}{
+		{nil, "23845b11cf32907fcf48263ad517aabff0c1033fec8814210dc941d3ba154271"},
+		{[]byte("key info"), "f1d46bff770bbcdf068e17ffc809824824dba2450cf49157a4387fa8df2dd5e"},
+	}
+	for _, vector := range vectors {
+		sk, err := KeyGen(*params, ikm, vector.keyInfo)
+		if err != nil {
+			t.Fatalf("KeyGen failed: %v", err)
+		}
+		expected, _ := new(big.Int).SetString(vector.expected, 16)
+		if sk.Cmp(expected) != 0 {
+			t.Fatalf("KeyGen(%q) = %x, expected %s", vector.keyInfo, sk, vector.expected)
+		}
+	}
+
+	if _, err := KeyGen(*params, ikm[:31], nil); err == nil {
+		t.Fatal("KeyGen was expected to reject IKM shorter than 32 bytes")
+	}
+}
+
+// TestKeyGenBLS12381 使用EIP-2333公开的测试向量(test case 0与1的master_SK)测试BLS12-381上的HKDF_mod_r。
+func TestKeyGenBLS12381(t *testing.T) {
+	vectors := []struct {
+		seed     string
+		expected string
+	}{
+		{
+			"c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04",
+			"6083874454709270928345386274498605044986640685124978867557563392430687146096",
+		},
+		{
+			"3141592653589793238462643383279502884197169399375105820974944592",
+			"29757020647961307431480504535336562678282505419141012933316116377660817309383",
+		},
+	}
+	for _, vector := range vectors {
+		seed, err := hex.DecodeString(vector.seed)
+		if err != nil {
+			t.Fatalf("invalid seed: %v", err)
+		}
+		sk, err := hkdfModR(seed, nil, ecc.BLS12_381.ScalarField())
+		if err != nil {
+			t.Fatalf("hkdfModR failed: %v", err)
+		}
+		expected, _ := new(big.Int).SetString(vector.expected, 10)
+		if sk.Cmp(expected) != 0 {
+			t.Fatalf("hkdfModR(%s) = %v, expected %s", vector.seed, sk, vector.expected)
+		}
+	}
+}
+
+// TestKeyValidate 测试公钥有效性检查。
+func TestKeyValidate(t *testing.T) {
+	params, err := SetUpWithCiphersuite(CiphersuiteBasic)
+	if err != nil {
+		t.Fatalf("SetUpWithCiphersuite failed: %v", err)
+	}
+	keyPair, err := KeyGeneration(*params)
+	if err != nil {
+		t.Fatalf("KeyGeneration failed: %v", err)
+	}
+	publicKey := SkToPk(*params, keyPair.PrivateKey)
+	if !publicKey.Equal(keyPair.PublicKey) {
+		t.Fatal("SkToPk does not match the generated public key")
+	}
+	if !KeyValidate(*params, publicKey) {
+		t.Fatal("a generated public key was expected to be valid")
+	}
+	if KeyValidate(*params, NewG1Point(bn254.G1Affine{})) {
+		t.Fatal("the point at infinity was expected to be invalid")
+	}
+}
+
+// TestCiphersuiteFlow 测试三种规范方案下的签名、验证与聚合验证。
+func TestCiphersuiteFlow(t *testing.T) {
+	suites := []Ciphersuite{CiphersuiteBasic, CiphersuiteMessageAugmentation, CiphersuiteProofOfPossession}
+	for _, suite := range suites {
+		t.Run(suite.String(), func(t *testing.T) {
+			params, err := SetUpWithCiphersuite(suite)
+			if err != nil {
+				t.Fatalf("SetUpWithCiphersuite failed: %v", err)
+			}
+			id, _ := suite.ID()
+			if string(params.DST) != id {
+				t.Fatalf("unexpected DST %q", params.DST)
+			}
+
+			n := 3
+			publicKeys := make([]G1Point, n)
+			messages := make([][]byte, n)
+			signatures := make([]BLSSignature, n)
+			for i := 0; i < n; i++ {
+				keyPair, err := KeyGeneration(*params)
+				if err != nil {
+					t.Fatalf("KeyGeneration failed: %v", err)
+				}
+				messages[i] = []byte(fmt.Sprintf("message %d", i))
+				signature, err := Sign(*params, keyPair.PrivateKey, messages[i])
+				if err != nil {
+					t.Fatalf("Sign failed: %v", err)
+				}
+				isValid, err := Verify(*params, keyPair.PublicKey, *signature)
+				if err != nil || !isValid {
+					t.Fatalf("Verify failed: %v", err)
+				}
+				publicKeys[i] = keyPair.PublicKey
+				signatures[i] = *signature
+			}
+
+			aggregateSignature, err := Aggregate(signatures)
+			if err != nil {
+				t.Fatalf("Aggregate failed: %v", err)
+			}
+			isValid, err := AggregateVerify(*params, publicKeys, messages, *aggregateSignature)
+			if err != nil || !isValid {
+				t.Fatalf("AggregateVerify failed: %v", err)
+			}
+
+			// 同一消息: BASIC方案拒绝重复消息,其他方案允许
+			message := []byte("same message")
+			for i := 0; i < n; i++ {
+				messages[i] = message
+			}
+			_, err = AggregateVerify(*params, publicKeys, messages, *aggregateSignature)
+			if suite == CiphersuiteBasic && err == nil {
+				t.Fatal("BASIC AggregateVerify was expected to reject duplicate messages")
+			}
+			if suite != CiphersuiteBasic && err != nil {
+				t.Fatalf("AggregateVerify failed unexpectedly: %v", err)
+			}
+
+			_, err = FastAggregateVerify(*params, publicKeys, message, *aggregateSignature)
+			if suite == CiphersuiteProofOfPossession && err != nil {
+				t.Fatalf("FastAggregateVerify failed unexpectedly: %v", err)
+			}
+			if suite != CiphersuiteProofOfPossession && err == nil {
+				t.Fatal("FastAggregateVerify was expected to be rejected outside PROOF-OF-POSSESSION")
+			}
+		})
+	}
+}
+
+// TestMessageAugmentationBindsPublicKey 测试MESSAGE-AUGMENTATION方案下签名与公钥绑定。
+func TestMessageAugmentationBindsPublicKey(t *testing.T) {
+	basicParams, err := SetUpWithCiphersuite(CiphersuiteBasic)
+	if err != nil {
+		t.Fatalf("SetUpWithCiphersuite failed: %v", err)
+	}
+	augParams, err := SetUpWithCiphersuite(CiphersuiteMessageAugmentation)
+	if err != nil {
+		t.Fatalf("SetUpWithCiphersuite failed: %v", err)
+	}
+	keyPair, err := KeyGeneration(*augParams)
+	if err != nil {
+		t.Fatalf("KeyGeneration failed: %v", err)
+	}
+
+	message := []byte("augmented")
+	signature, err := Sign(*augParams, keyPair.PrivateKey, message)
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+
+	// 用BASIC方案的参数,并手动拼接公钥和改写DST,应与AUG签名一致
+	publicKeyBytes := keyPair.PublicKey.Bytes()
+	manualParams := *basicParams
+	manualParams.DST = augParams.DST
+	manual, err := Sign(manualParams, keyPair.PrivateKey, append(publicKeyBytes[:], message...))
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+	if !manual.Signature.Equal(signature.Signature) {
+		t.Fatal("MESSAGE-AUGMENTATION signature does not sign pk || message")
+	}
+}
@@ -0,0 +1,539 @@
+package bls
+
+// 多曲线BLS签名
+//
+// 所有接口都通过BLSParams.Curve选择曲线,公钥、签名等群元素使用G1Point和G2Point表示,
+// 点所在的曲线必须与参数的曲线一致。各曲线的压缩编码长度与hash-to-curve套件为:
+//
+//	| 曲线      | ecc.ID        | G1     | G2     | hash-to-curve套件(G1 / G2)                                      |
+//	|-----------|---------------|--------|--------|-----------------------------------------------------------------|
+//	| BN254     | ecc.BN254     | 32字节 | 64字节 | BN254G1_XMD:SHA-256_SVDW_RO_ / BN254G2_XMD:SHA-256_SVDW_RO_     |
+//	| BLS12-381 | ecc.BLS12_381 | 48字节 | 96字节 | BLS12381G1_XMD:SHA-256_SSWU_RO_ / BLS12381G2_XMD:SHA-256_SSWU_RO_ |
+//	| BLS12-377 | ecc.BLS12_377 | 48字节 | 96字节 | BLS12377G1_XMD:SHA-256_SSWU_RO_ / BLS12377G2_XMD:SHA-256_SSWU_RO_ |
+//	| BW6-761   | ecc.BW6_761   | 96字节 | 96字节 | BW6761G1_XMD:SHA-256_SSWU_RO_ / BW6761G2_XMD:SHA-256_SSWU_RO_   |
+//
+// BLS12-381在PROOF-OF-POSSESSION方案下的DST为"BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_",
+// 与以太坊共识层的签名互通。
+//
+// 每条曲线由一个curveBackend实现,曲线之间只有gnark-crypto的类型与少量函数不同,
+// 编码、有效性检查等逻辑由泛型的pointGroup与pairingCurve共享,bls_curve_*.go只负责实例化。
+
+import (
+	"errors"
+	"fmt"
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	bw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761"
+	"math/big"
+)
+
+// ErrCurveMismatch 表示点不属于参数所选的曲线(包括G1Point、G2Point的零值)。
+var ErrCurveMismatch = errors.New("point is not on the curve of the params")
+
+// G1Affine 为支持的曲线上G1群的仿射点类型。
+type G1Affine interface {
+	bn254.G1Affine | bls12381.G1Affine | bls12377.G1Affine | bw6761.G1Affine
+}
+
+// G2Affine 为支持的曲线上G2群的仿射点类型。
+type G2Affine interface {
+	bn254.G2Affine | bls12381.G2Affine | bls12377.G2Affine | bw6761.G2Affine
+}
+
+// G1Point 表示某条曲线上G1群中的点,由NewG1Point或本包的函数创建。零值不属于任何曲线。
+type G1Point struct {
+	p point
+}
+
+// G2Point 表示某条曲线上G2群中的点,由NewG2Point或本包的函数创建。零值不属于任何曲线。
+type G2Point struct {
+	p point
+}
+
+// NewG1Point 将gnark-crypto的G1点(例如bn254.G1Affine)转换为G1Point。
+func NewG1Point[T G1Affine](point T) G1Point {
+	for _, backend := range backends {
+		if p, ok := backend.g1().wrap(point); ok {
+			return G1Point{p}
+		}
+	}
+	panic("bls: unsupported G1 type")
+}
+
+// NewG2Point 将gnark-crypto的G2点(例如bn254.G2Affine)转换为G2Point。
+func NewG2Point[T G2Affine](point T) G2Point {
+	for _, backend := range backends {
+		if p, ok := backend.g2().wrap(point); ok {
+			return G2Point{p}
+		}
+	}
+	panic("bls: unsupported G2 type")
+}
+
+// Curve 返回点所在的曲线,零值返回ecc.UNKNOWN。
+func (point G1Point) Curve() ecc.ID {
+	return curveOfPoint(point.p)
+}
+
+// Affine 返回gnark-crypto表示的点,例如BN254上为bn254.G1Affine,零值返回nil。
+func (point G1Point) Affine() any {
+	if point.p == nil {
+		return nil
+	}
+	return point.p.affine()
+}
+
+// Equal 判断两个点是否在同一曲线上且相等。
+func (point G1Point) Equal(other G1Point) bool {
+	return equalPoints(point.p, other.p)
+}
+
+// Bytes 返回点的压缩编码,零值返回nil。
+func (point G1Point) Bytes() []byte {
+	return marshalPoint(point.p, true)
+}
+
+// Curve 返回点所在的曲线,零值返回ecc.UNKNOWN。
+func (point G2Point) Curve() ecc.ID {
+	return curveOfPoint(point.p)
+}
+
+// Affine 返回gnark-crypto表示的点,例如BN254上为bn254.G2Affine,零值返回nil。
+func (point G2Point) Affine() any {
+	if point.p == nil {
+		return nil
+	}
+	return point.p.affine()
+}
+
+// Equal 判断两个点是否在同一曲线上且相等。
+func (point G2Point) Equal(other G2Point) bool {
+	return equalPoints(point.p, other.p)
+}
+
+// Bytes 返回点的压缩编码,零值返回nil。
+func (point G2Point) Bytes() []byte {
+	return marshalPoint(point.p, true)
+}
+
+// SupportedCurves 返回支持的曲线。
+func SupportedCurves() []ecc.ID {
+	curves := make([]ecc.ID, len(backends))
+	for i, backend := range backends {
+		curves[i] = backend.curve()
+	}
+	return curves
+}
+
+// SetUpOnCurve 在指定曲线上初始化BLS参数,DST与SetUp相同。
+func SetUpOnCurve(curve ecc.ID) (*BLSParams, error) {
+	backend, err := backendFor(curve)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up bls params: %w", err)
+	}
+	return &BLSParams{
+		G1Generator: G1Point{backend.g1().generator()},
+		G2Generator: G2Point{backend.g2().generator()},
+		DST:         []byte("bls Signature"),
+		PopDST:      []byte("bls Proof of Possession"),
+		PrehashDST:  []byte("bls Prehash Signature"),
+		Curve:       curve,
+	}, nil
+}
+
+// SetUpOnCurveWithCiphersuite 在指定曲线上按照规范方案初始化BLS参数,
+// 签名DST为"BLS_SIG_" || G2的hash-to-curve套件 || "{NUL,AUG,POP}_"。
+func SetUpOnCurveWithCiphersuite(curve ecc.ID, suite Ciphersuite) (*BLSParams, error) {
+	return setUpWithSuite(curve, suite, VariantMinPublicKeySize)
+}
+
+// setUpWithSuite 按照规范方案初始化参数,hash-to-curve套件取签名所在的群。
+func setUpWithSuite(curve ecc.ID, suite Ciphersuite, variant Variant) (*BLSParams, error) {
+	blsParams, err := SetUpOnCurve(curve)
+	if err != nil {
+		return nil, err
+	}
+	backend, _ := backendFor(curve)
+	signatureGroup := backend.g2()
+	if variant == VariantMinSignatureSize {
+		signatureGroup = backend.g1()
+	}
+	id, err := suite.id(signatureGroup.hashToCurveSuite())
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up bls params: %v", err)
+	}
+	blsParams.DST = []byte(id)
+	blsParams.PopDST = []byte("BLS_POP_" + signatureGroup.hashToCurveSuite() + "POP_")
+	blsParams.PrehashDST = []byte(id + prehashTag)
+	blsParams.Ciphersuite = suite
+	blsParams.Variant = variant
+	return blsParams, nil
+}
+
+// curveOf 返回参数的曲线,未设置曲线的参数视为BN254。
+func curveOf(blsParams BLSParams) ecc.ID {
+	if blsParams.Curve == ecc.UNKNOWN {
+		return ecc.BN254
+	}
+	return blsParams.Curve
+}
+
+func backendFor(curve ecc.ID) (curveBackend, error) {
+	for _, backend := range backends {
+		if backend.curve() == curve {
+			return backend, nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported curve: %v", curve)
+}
+
+// backendOf 返回参数曲线对应的实现,并检查参数的生成元位于该曲线上、变体与调用的函数一致。
+func backendOf(blsParams BLSParams, expected Variant) (curveBackend, error) {
+	backend, err := backendFor(curveOf(blsParams))
+	if err != nil {
+		return nil, err
+	}
+	if blsParams.G1Generator.Curve() != backend.curve() || blsParams.G2Generator.Curve() != backend.curve() {
+		return nil, fmt.Errorf("params generators are not on curve %v", backend.curve())
+	}
+	if blsParams.Variant != expected {
+		return nil, fmt.Errorf("params are for the %v variant, expected %v", blsParams.Variant, expected)
+	}
+	return backend, nil
+}
+
+// checkPrivateKey 检查私钥位于[1, r)中。
+func checkPrivateKey(backend curveBackend, privateKey *big.Int) error {
+	if privateKey == nil || privateKey.Sign() <= 0 || privateKey.Cmp(backend.curve().ScalarField()) >= 0 {
+		return ErrInvalidPrivateKey
+	}
+	return nil
+}
+
+// validatePoint 检查点属于曲线curve、不是无穷远点、在曲线上且位于素数阶子群中。
+func validatePoint(curve ecc.ID, p point) error {
+	if curveOfPoint(p) != curve {
+		return ErrCurveMismatch
+	}
+	return p.validate()
+}
+
+func curveOfPoint(p point) ecc.ID {
+	if p == nil {
+		return ecc.UNKNOWN
+	}
+	return p.curve()
+}
+
+func equalPoints(p point, q point) bool {
+	if p == nil || q == nil {
+		return p == nil && q == nil
+	}
+	return p.equal(q)
+}
+
+func marshalPoint(p point, compressed bool) []byte {
+	if p == nil {
+		return nil
+	}
+	return p.bytes(compressed)
+}
+
+// point 为某条曲线上G1或G2群中的点,运算的另一个操作数必须是同一个群中的点。
+type point interface {
+	curve() ecc.ID
+	add(q point) point
+	sub(q point) point
+	neg() point
+	mul(k *big.Int) point
+	equal(q point) bool
+	isInfinity() bool
+	// validate 检查点不是无穷远点、在曲线上且位于素数阶子群中
+	validate() error
+	bytes(compressed bool) []byte
+	affine() any
+}
+
+// group 为某条曲线上G1或G2群的运算。
+type group interface {
+	generator() point
+	infinity() point
+	hashToCurveSuite() string
+	hashToCurve(message []byte, dst []byte) (point, error)
+	// unmarshal 解码压缩或未压缩的点并检查其有效性,失败时返回*DecodeError
+	unmarshal(object string, data []byte) (point, error)
+	compressedSize() int
+	// wrap 在value为该群的gnark-crypto类型时返回对应的点
+	wrap(value any) (point, bool)
+}
+
+// curveBackend 为一条曲线上BLS签名所需的运算。
+type curveBackend interface {
+	curve() ecc.ID
+	// name 返回曲线在DST中的名称,例如"BN254"
+	name() string
+	g1() group
+	g2() group
+	// pairingCheck 检查e(p_1, q_1) * ... * e(p_n, q_n) = 1,p_i位于G1,q_i位于G2
+	pairingCheck(p []point, q []point) (bool, error)
+	// precomputeLines 预计算G2上的点q的Miller循环直线
+	precomputeLines(q point) any
+	// pairingCheckFixedQ 与pairingCheck相同,但前len(lines)个配对的G2点由预计算的直线给出
+	pairingCheckFixedQ(fixed []point, lines []any, p []point, q []point) (bool, error)
+}
+
+// affinePoint 约束gnark-crypto各曲线G1Affine与G2Affine的指针类型。
+type affinePoint[T any] interface {
+	*T
+	ScalarMultiplication(a *T, s *big.Int) *T
+	Add(a *T, b *T) *T
+	Sub(a *T, b *T) *T
+	Neg(a *T) *T
+	Equal(a *T) bool
+	SetInfinity() *T
+	IsInfinity() bool
+	IsOnCurve() bool
+	IsInSubGroup() bool
+}
+
+// pointGroup 是group的泛型实现,T为gnark-crypto的仿射点类型。
+type pointGroup[T any, P affinePoint[T]] struct {
+	id     ecc.ID
+	g      T
+	suite  string
+	size   int
+	hash   func(message []byte, dst []byte) (T, error)
+	encode func(value any, compressed bool) []byte
+	// decode 不做子群检查,返回读取的字节数
+	decode func(data []byte, value any) (int64, error)
+}
+
+// groupPoint 是point的泛型实现。
+type groupPoint[T any, P affinePoint[T]] struct {
+	group *pointGroup[T, P]
+	value T
+}
+
+func (g *pointGroup[T, P]) point(value T) point {
+	return &groupPoint[T, P]{group: g, value: value}
+}
+
+func (g *pointGroup[T, P]) generator() point {
+	return g.point(g.g)
+}
+
+func (g *pointGroup[T, P]) infinity() point {
+	var value T
+	P(&value).SetInfinity()
+	return g.point(value)
+}
+
+func (g *pointGroup[T, P]) hashToCurveSuite() string {
+	return g.suite
+}
+
+func (g *pointGroup[T, P]) hashToCurve(message []byte, dst []byte) (point, error) {
+	value, err := g.hash(message, dst)
+	if err != nil {
+		return nil, err
+	}
+	return g.point(value), nil
+}
+
+func (g *pointGroup[T, P]) unmarshal(object string, data []byte) (point, error) {
+	if len(data) != g.size && len(data) != 2*g.size {
+		return nil, &DecodeError{Object: object, Err: ErrInvalidLength}
+	}
+	var value T
+	bytesRead, err := g.decode(data, &value)
+	if err != nil {
+		return nil, &DecodeError{Object: object, Err: ErrInvalidEncoding}
+	}
+	// 压缩标记必须与长度一致,不允许有多余的字节
+	if bytesRead != int64(len(data)) {
+		return nil, &DecodeError{Object: object, Err: ErrInvalidLength}
+	}
+	p := g.point(value)
+	if err := p.validate(); err != nil {
+		return nil, &DecodeError{Object: object, Err: err}
+	}
+	return p, nil
+}
+
+func (g *pointGroup[T, P]) compressedSize() int {
+	return g.size
+}
+
+func (g *pointGroup[T, P]) wrap(value any) (point, bool) {
+	v, ok := value.(T)
+	if !ok {
+		return nil, false
+	}
+	return g.point(v), true
+}
+
+// of 返回q在该群中的值,q必须属于同一个群。
+func (g *pointGroup[T, P]) of(q point) *T {
+	return &q.(*groupPoint[T, P]).value
+}
+
+func (p *groupPoint[T, P]) curve() ecc.ID {
+	return p.group.id
+}
+
+func (p *groupPoint[T, P]) add(q point) point {
+	var sum T
+	P(&sum).Add(&p.value, p.group.of(q))
+	return p.group.point(sum)
+}
+
+func (p *groupPoint[T, P]) sub(q point) point {
+	var difference T
+	P(&difference).Sub(&p.value, p.group.of(q))
+	return p.group.point(difference)
+}
+
+func (p *groupPoint[T, P]) neg() point {
+	var negation T
+	P(&negation).Neg(&p.value)
+	return p.group.point(negation)
+}
+
+func (p *groupPoint[T, P]) mul(k *big.Int) point {
+	var product T
+	P(&product).ScalarMultiplication(&p.value, k)
+	return p.group.point(product)
+}
+
+func (p *groupPoint[T, P]) equal(q point) bool {
+	other, ok := q.(*groupPoint[T, P])
+	return ok && p.group == other.group && P(&p.value).Equal(&other.value)
+}
+
+func (p *groupPoint[T, P]) isInfinity() bool {
+	return P(&p.value).IsInfinity()
+}
+
+func (p *groupPoint[T, P]) validate() error {
+	value := P(&p.value)
+	if value.IsInfinity() {
+		return ErrPointAtInfinity
+	}
+	if !value.IsOnCurve() {
+		return ErrNotOnCurve
+	}
+	if !value.IsInSubGroup() {
+		return ErrNotInSubgroup
+	}
+	return nil
+}
+
+func (p *groupPoint[T, P]) bytes(compressed bool) []byte {
+	return p.group.encode(&p.value, compressed)
+}
+
+func (p *groupPoint[T, P]) affine() any {
+	return p.value
+}
+
+// pairingCurve 是curveBackend的泛型实现,G1与G2为gnark-crypto的仿射点类型。
+type pairingCurve[G1 any, P1 affinePoint[G1], G2 any, P2 affinePoint[G2]] struct {
+	curveName string
+	group1    *pointGroup[G1, P1]
+	group2    *pointGroup[G2, P2]
+	pairing   func(p []G1, q []G2) (bool, error)
+	lines     func(q G2) any
+	fixedQ    func(fixed []G1, lines []any, p []G1, q []G2) (bool, error)
+}
+
+func (c *pairingCurve[G1, P1, G2, P2]) curve() ecc.ID {
+	return c.group1.id
+}
+
+func (c *pairingCurve[G1, P1, G2, P2]) name() string {
+	return c.curveName
+}
+
+func (c *pairingCurve[G1, P1, G2, P2]) g1() group {
+	return c.group1
+}
+
+func (c *pairingCurve[G1, P1, G2, P2]) g2() group {
+	return c.group2
+}
+
+func (c *pairingCurve[G1, P1, G2, P2]) pairingCheck(p []point, q []point) (bool, error) {
+	return c.pairing(c.values1(p), c.values2(q))
+}
+
+func (c *pairingCurve[G1, P1, G2, P2]) precomputeLines(q point) any {
+	return c.lines(*c.group2.of(q))
+}
+
+func (c *pairingCurve[G1, P1, G2, P2]) pairingCheckFixedQ(fixed []point, lines []any, p []point, q []point) (bool, error) {
+	return c.fixedQ(c.values1(fixed), lines, c.values1(p), c.values2(q))
+}
+
+func (c *pairingCurve[G1, P1, G2, P2]) values1(points []point) []G1 {
+	values := make([]G1, len(points))
+	for i := range points {
+		values[i] = *c.group1.of(points[i])
+	}
+	return values
+}
+
+func (c *pairingCurve[G1, P1, G2, P2]) values2(points []point) []G2 {
+	values := make([]G2, len(points))
+	for i := range points {
+		values[i] = *c.group2.of(points[i])
+	}
+	return values
+}
+
+// backends 为所有支持的曲线,SupportedCurves按此顺序返回。
+var backends = []curveBackend{bn254Curve, bls12381Curve, bls12377Curve, bw6761Curve}
+
+// gtElement 约束gnark-crypto各曲线GT类型的指针类型。
+type gtElement[E any] interface {
+	*E
+	Mul(a *E, b *E) *E
+	IsOne() bool
+}
+
+// precomputeLinesAny 将曲线的PrecomputeLines包装为返回any的函数。
+func precomputeLinesAny[G2 any, L any](precompute func(q G2) L) func(q G2) any {
+	return func(q G2) any {
+		return precompute(q)
+	}
+}
+
+// pairingCheckWithLines 由曲线的Miller循环函数构造pairingCurve.fixedQ:
+// 预计算直线的配对使用millerLoopFixedQ,其余配对使用millerLoop,最后做一次最终幂运算。
+func pairingCheckWithLines[G1 any, G2 any, L any, E any, PE gtElement[E]](
+	millerLoopFixedQ func(p []G1, lines []L) (E, error),
+	millerLoop func(p []G1, q []G2) (E, error),
+	finalExponentiation func(z *E, _ ...*E) E,
+) func(fixed []G1, lines []any, p []G1, q []G2) (bool, error) {
+	return func(fixed []G1, lines []any, p []G1, q []G2) (bool, error) {
+		// millerLoopFixedQ会在原地用P的坐标改写传入的直线,类型断言复制了数组,预计算的直线不会被改写
+		copied := make([]L, len(lines))
+		for i := range lines {
+			copied[i] = lines[i].(L)
+		}
+		result, err := millerLoopFixedQ(fixed, copied)
+		if err != nil {
+			return false, err
+		}
+		if len(p) > 0 {
+			variable, err := millerLoop(p, q)
+			if err != nil {
+				return false, err
+			}
+			PE(&result).Mul(&result, &variable)
+		}
+		result = finalExponentiation(&result)
+		return PE(&result).IsOne(), nil
+	}
+}
@@ -0,0 +1,55 @@
+package bls
+
+import (
+	"bytes"
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
+)
+
+// bls12377Curve 为BLS12-377上的BLS签名。
+var bls12377Curve curveBackend = func() curveBackend {
+	_, _, g1, g2 := bls12377.Generators()
+	return &pairingCurve[bls12377.G1Affine, *bls12377.G1Affine, bls12377.G2Affine, *bls12377.G2Affine]{
+		curveName: "BLS12377",
+		group1: &pointGroup[bls12377.G1Affine, *bls12377.G1Affine]{
+			id:     ecc.BLS12_377,
+			g:      g1,
+			suite:  "BLS12377G1_XMD:SHA-256_SSWU_RO_",
+			size:   bls12377.SizeOfG1AffineCompressed,
+			hash:   bls12377.HashToG1,
+			encode: encodeBLS12377,
+			decode: decodeBLS12377,
+		},
+		group2: &pointGroup[bls12377.G2Affine, *bls12377.G2Affine]{
+			id:     ecc.BLS12_377,
+			g:      g2,
+			suite:  "BLS12377G2_XMD:SHA-256_SSWU_RO_",
+			size:   bls12377.SizeOfG2AffineCompressed,
+			hash:   bls12377.HashToG2,
+			encode: encodeBLS12377,
+			decode: decodeBLS12377,
+		},
+		pairing: bls12377.PairingCheck,
+		lines:   precomputeLinesAny(bls12377.PrecomputeLines),
+		fixedQ:  pairingCheckWithLines(bls12377.MillerLoopFixedQ, bls12377.MillerLoop, bls12377.FinalExponentiation),
+	}
+}()
+
+func encodeBLS12377(value any, compressed bool) []byte {
+	var buffer bytes.Buffer
+	encoder := bls12377.NewEncoder(&buffer)
+	if !compressed {
+		encoder = bls12377.NewEncoder(&buffer, bls12377.RawEncoding())
+	}
+	if err := encoder.Encode(value); err != nil {
+		panic(err)
+	}
+	return buffer.Bytes()
+}
+
+// decodeBLS12377 解码点但不做子群检查,子群检查由pointGroup.unmarshal统一完成。
+func decodeBLS12377(data []byte, value any) (int64, error) {
+	decoder := bls12377.NewDecoder(bytes.NewReader(data), bls12377.NoSubgroupChecks())
+	err := decoder.Decode(value)
+	return decoder.BytesRead(), err
+}
@@ -0,0 +1,55 @@
+package bls
+
+import (
+	"bytes"
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+)
+
+// bls12381Curve 为BLS12-381上的BLS签名,编码与以太坊共识层一致。
+var bls12381Curve curveBackend = func() curveBackend {
+	_, _, g1, g2 := bls12381.Generators()
+	return &pairingCurve[bls12381.G1Affine, *bls12381.G1Affine, bls12381.G2Affine, *bls12381.G2Affine]{
+		curveName: "BLS12381",
+		group1: &pointGroup[bls12381.G1Affine, *bls12381.G1Affine]{
+			id:     ecc.BLS12_381,
+			g:      g1,
+			suite:  "BLS12381G1_XMD:SHA-256_SSWU_RO_",
+			size:   bls12381.SizeOfG1AffineCompressed,
+			hash:   bls12381.HashToG1,
+			encode: encodeBLS12381,
+			decode: decodeBLS12381,
+		},
+		group2: &pointGroup[bls12381.G2Affine, *bls12381.G2Affine]{
+			id:     ecc.BLS12_381,
+			g:      g2,
+			suite:  "BLS12381G2_XMD:SHA-256_SSWU_RO_",
+			size:   bls12381.SizeOfG2AffineCompressed,
+			hash:   bls12381.HashToG2,
+			encode: encodeBLS12381,
+			decode: decodeBLS12381,
+		},
+		pairing: bls12381.PairingCheck,
+		lines:   precomputeLinesAny(bls12381.PrecomputeLines),
+		fixedQ:  pairingCheckWithLines(bls12381.MillerLoopFixedQ, bls12381.MillerLoop, bls12381.FinalExponentiation),
+	}
+}()
+
+func encodeBLS12381(value any, compressed bool) []byte {
+	var buffer bytes.Buffer
+	encoder := bls12381.NewEncoder(&buffer)
+	if !compressed {
+		encoder = bls12381.NewEncoder(&buffer, bls12381.RawEncoding())
+	}
+	if err := encoder.Encode(value); err != nil {
+		panic(err)
+	}
+	return buffer.Bytes()
+}
+
+// decodeBLS12381 解码点但不做子群检查,子群检查由pointGroup.unmarshal统一完成。
+func decodeBLS12381(data []byte, value any) (int64, error) {
+	decoder := bls12381.NewDecoder(bytes.NewReader(data), bls12381.NoSubgroupChecks())
+	err := decoder.Decode(value)
+	return decoder.BytesRead(), err
+}
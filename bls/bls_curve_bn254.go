@@ -0,0 +1,55 @@
+package bls
+
+import (
+	"bytes"
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+)
+
+// bn254Curve 为BN254上的BLS签名,G1与G2的hash-to-curve使用SVDW映射,是SetUp的默认曲线。
+var bn254Curve curveBackend = func() curveBackend {
+	_, _, g1, g2 := bn254.Generators()
+	return &pairingCurve[bn254.G1Affine, *bn254.G1Affine, bn254.G2Affine, *bn254.G2Affine]{
+		curveName: "BN254",
+		group1: &pointGroup[bn254.G1Affine, *bn254.G1Affine]{
+			id:     ecc.BN254,
+			g:      g1,
+			suite:  hashToCurveSuiteG1,
+			size:   bn254.SizeOfG1AffineCompressed,
+			hash:   bn254.HashToG1,
+			encode: encodeBN254,
+			decode: decodeBN254,
+		},
+		group2: &pointGroup[bn254.G2Affine, *bn254.G2Affine]{
+			id:     ecc.BN254,
+			g:      g2,
+			suite:  hashToCurveSuiteG2,
+			size:   bn254.SizeOfG2AffineCompressed,
+			hash:   bn254.HashToG2,
+			encode: encodeBN254,
+			decode: decodeBN254,
+		},
+		pairing: bn254.PairingCheck,
+		lines:   precomputeLinesAny(bn254.PrecomputeLines),
+		fixedQ:  pairingCheckWithLines(bn254.MillerLoopFixedQ, bn254.MillerLoop, bn254.FinalExponentiation),
+	}
+}()
+
+func encodeBN254(value any, compressed bool) []byte {
+	var buffer bytes.Buffer
+	encoder := bn254.NewEncoder(&buffer)
+	if !compressed {
+		encoder = bn254.NewEncoder(&buffer, bn254.RawEncoding())
+	}
+	if err := encoder.Encode(value); err != nil {
+		panic(err)
+	}
+	return buffer.Bytes()
+}
+
+// decodeBN254 解码点但不做子群检查,子群检查由pointGroup.unmarshal统一完成。
+func decodeBN254(data []byte, value any) (int64, error) {
+	decoder := bn254.NewDecoder(bytes.NewReader(data), bn254.NoSubgroupChecks())
+	err := decoder.Decode(value)
+	return decoder.BytesRead(), err
+}
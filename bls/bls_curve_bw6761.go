@@ -0,0 +1,55 @@
+package bls
+
+import (
+	"bytes"
+	"github.com/consensys/gnark-crypto/ecc"
+	bw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761"
+)
+
+// bw6761Curve 为BW6-761上的BLS签名,G1与G2的压缩编码均为96字节。
+var bw6761Curve curveBackend = func() curveBackend {
+	_, _, g1, g2 := bw6761.Generators()
+	return &pairingCurve[bw6761.G1Affine, *bw6761.G1Affine, bw6761.G2Affine, *bw6761.G2Affine]{
+		curveName: "BW6761",
+		group1: &pointGroup[bw6761.G1Affine, *bw6761.G1Affine]{
+			id:     ecc.BW6_761,
+			g:      g1,
+			suite:  "BW6761G1_XMD:SHA-256_SSWU_RO_",
+			size:   bw6761.SizeOfG1AffineCompressed,
+			hash:   bw6761.HashToG1,
+			encode: encodeBW6761,
+			decode: decodeBW6761,
+		},
+		group2: &pointGroup[bw6761.G2Affine, *bw6761.G2Affine]{
+			id:     ecc.BW6_761,
+			g:      g2,
+			suite:  "BW6761G2_XMD:SHA-256_SSWU_RO_",
+			size:   bw6761.SizeOfG2AffineCompressed,
+			hash:   bw6761.HashToG2,
+			encode: encodeBW6761,
+			decode: decodeBW6761,
+		},
+		pairing: bw6761.PairingCheck,
+		lines:   precomputeLinesAny(bw6761.PrecomputeLines),
+		fixedQ:  pairingCheckWithLines(bw6761.MillerLoopFixedQ, bw6761.MillerLoop, bw6761.FinalExponentiation),
+	}
+}()
+
+func encodeBW6761(value any, compressed bool) []byte {
+	var buffer bytes.Buffer
+	encoder := bw6761.NewEncoder(&buffer)
+	if !compressed {
+		encoder = bw6761.NewEncoder(&buffer, bw6761.RawEncoding())
+	}
+	if err := encoder.Encode(value); err != nil {
+		panic(err)
+	}
+	return buffer.Bytes()
+}
+
+// decodeBW6761 解码点但不做子群检查,子群检查由pointGroup.unmarshal统一完成。
+func decodeBW6761(data []byte, value any) (int64, error) {
+	decoder := bw6761.NewDecoder(bytes.NewReader(data), bw6761.NoSubgroupChecks())
+	err := decoder.Decode(value)
+	return decoder.BytesRead(), err
+}
@@ -0,0 +1,379 @@
+package bls
+
+import (
+	"encoding/hex"
+	"errors"
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"math/big"
+	"testing"
+)
+
+// TestCurveFlow 测试所有支持的曲线上的签名、聚合签名与持有性证明。
+func TestCurveFlow(t *testing.T) {
+	for _, curve := range SupportedCurves() {
+		for _, suite := range []Ciphersuite{CiphersuiteBasic, CiphersuiteMessageAugmentation, CiphersuiteProofOfPossession} {
+			params, err := SetUpOnCurveWithCiphersuite(curve, suite)
+			if err != nil {
+				t.Fatalf("SetUpOnCurveWithCiphersuite failed: %v", err)
+			}
+			keyPairs := make([]*BLSKeyPair, 3)
+			signatures := make([]BLSSignature, 3)
+			publicKeys := make([]G1Point, 3)
+			messages := make([][]byte, 3)
+			for i := range keyPairs {
+				keyPairs[i], err = KeyGeneration(*params)
+				if err != nil {
+					t.Fatalf("KeyGeneration failed: %v", err)
+				}
+				if keyPairs[i].PublicKey.Curve() != curve {
+					t.Fatalf("%v: public key is on curve %v", curve, keyPairs[i].PublicKey.Curve())
+				}
+				publicKeys[i] = keyPairs[i].PublicKey
+				messages[i] = []byte{byte(i), 'm'}
+				signature, err := Sign(*params, keyPairs[i].PrivateKey, messages[i])
+				if err != nil {
+					t.Fatalf("Sign failed: %v", err)
+				}
+				signatures[i] = *signature
+				isValid, err := Verify(*params, publicKeys[i], *signature)
+				if err != nil || !isValid {
+					t.Fatalf("%v/%v: Verify failed: %v", curve, suite, err)
+				}
+			}
+			isValid, err := Verify(*params, publicKeys[1], signatures[0])
+			if err != nil || isValid {
+				t.Fatalf("%v/%v: Verify accepted a signature under another key", curve, suite)
+			}
+
+			aggregateSignature, err := Aggregate(signatures)
+			if err != nil {
+				t.Fatalf("Aggregate failed: %v", err)
+			}
+			isValid, err = AggregateVerify(*params, publicKeys, messages, *aggregateSignature)
+			if err != nil || !isValid {
+				t.Fatalf("%v/%v: AggregateVerify failed: %v", curve, suite, err)
+			}
+
+			if suite != CiphersuiteProofOfPossession {
+				continue
+			}
+			message := []byte("same message")
+			for i, keyPair := range keyPairs {
+				proof, err := PopProve(*params, keyPair.PrivateKey)
+				if err != nil {
+					t.Fatalf("PopProve failed: %v", err)
+				}
+				isValid, err := PopVerify(*params, keyPair.PublicKey, *proof)
+				if err != nil || !isValid {
+					t.Fatalf("%v: PopVerify failed: %v", curve, err)
+				}
+				signature, err := Sign(*params, keyPair.PrivateKey, message)
+				if err != nil {
+					t.Fatalf("Sign failed: %v", err)
+				}
+				signatures[i] = *signature
+			}
+			aggregateSignature, err = Aggregate(signatures)
+			if err != nil {
+				t.Fatalf("Aggregate failed: %v", err)
+			}
+			isValid, err = FastAggregateVerify(*params, publicKeys, message, *aggregateSignature)
+			if err != nil || !isValid {
+				t.Fatalf("%v: FastAggregateVerify failed: %v", curve, err)
+			}
+		}
+	}
+}
+
+// TestCurveEncoding 测试所有曲线上私钥、公钥与签名的编码长度及往返解码。
+func TestCurveEncoding(t *testing.T) {
+	sizes := map[ecc.ID][3]int{
+		ecc.BN254:     {32, 32, 64},
+		ecc.BLS12_381: {32, 48, 96},
+		ecc.BLS12_377: {32, 48, 96},
+		ecc.BW6_761:   {48, 96, 96},
+	}
+	for _, curve := range SupportedCurves() {
+		params, err := SetUpOnCurve(curve)
+		if err != nil {
+			t.Fatalf("SetUpOnCurve failed: %v", err)
+		}
+		keyPair, err := KeyGeneration(*params)
+		if err != nil {
+			t.Fatalf("KeyGeneration failed: %v", err)
+		}
+		signature, err := Sign(*params, keyPair.PrivateKey, []byte("encoded"))
+		if err != nil {
+			t.Fatalf("Sign failed: %v", err)
+		}
+
+		encodedPrivateKey, err := MarshalPrivateKey(*params, keyPair.PrivateKey)
+		if err != nil {
+			t.Fatalf("MarshalPrivateKey failed: %v", err)
+		}
+		encodedPublicKey := MarshalPublicKey(keyPair.PublicKey, true)
+		encodedSignature := MarshalSignature(signature.Signature, true)
+		expected := sizes[curve]
+		if len(encodedPrivateKey) != expected[0] || len(encodedPublicKey) != expected[1] || len(encodedSignature) != expected[2] {
+			t.Fatalf("%v: unexpected sizes %d, %d, %d", curve, len(encodedPrivateKey), len(encodedPublicKey), len(encodedSignature))
+		}
+
+		privateKey, err := UnmarshalPrivateKey(*params, encodedPrivateKey)
+		if err != nil || privateKey.Cmp(keyPair.PrivateKey) != 0 {
+			t.Fatalf("%v: UnmarshalPrivateKey failed: %v", curve, err)
+		}
+		for _, compressed := range []bool{true, false} {
+			publicKey, err := UnmarshalPublicKey(*params, MarshalPublicKey(keyPair.PublicKey, compressed))
+			if err != nil || !publicKey.Equal(keyPair.PublicKey) {
+				t.Fatalf("%v: UnmarshalPublicKey failed: %v", curve, err)
+			}
+			decodedSignature, err := UnmarshalSignature(*params, MarshalSignature(signature.Signature, compressed))
+			if err != nil || !decodedSignature.Equal(signature.Signature) {
+				t.Fatalf("%v: UnmarshalSignature failed: %v", curve, err)
+			}
+		}
+	}
+}
+
+// TestCurveMinSig 测试所有曲线上签名位于G1的变体。
+func TestCurveMinSig(t *testing.T) {
+	message := []byte("same message")
+	for _, curve := range SupportedCurves() {
+		params, err := SetUpMinSigOnCurveWithCiphersuite(curve, CiphersuiteProofOfPossession)
+		if err != nil {
+			t.Fatalf("SetUpMinSigOnCurveWithCiphersuite failed: %v", err)
+		}
+		publicKeys := make([]G2Point, 3)
+		signatures := make([]MinSigSignature, 3)
+		for i := range publicKeys {
+			keyPair, err := MinSigKeyGeneration(*params)
+			if err != nil {
+				t.Fatalf("MinSigKeyGeneration failed: %v", err)
+			}
+			proof, err := MinSigPopProve(*params, keyPair.PrivateKey)
+			if err != nil {
+				t.Fatalf("MinSigPopProve failed: %v", err)
+			}
+			isValid, err := MinSigPopVerify(*params, keyPair.PublicKey, *proof)
+			if err != nil || !isValid {
+				t.Fatalf("%v: MinSigPopVerify failed: %v", curve, err)
+			}
+			signature, err := MinSigSign(*params, keyPair.PrivateKey, message)
+			if err != nil {
+				t.Fatalf("MinSigSign failed: %v", err)
+			}
+			isValid, err = MinSigVerify(*params, keyPair.PublicKey, *signature)
+			if err != nil || !isValid {
+				t.Fatalf("%v: MinSigVerify failed: %v", curve, err)
+			}
+			publicKeys[i] = keyPair.PublicKey
+			signatures[i] = *signature
+		}
+		aggregateSignature, err := MinSigAggregate(signatures)
+		if err != nil {
+			t.Fatalf("MinSigAggregate failed: %v", err)
+		}
+		isValid, err := MinSigFastAggregateVerify(*params, publicKeys, message, *aggregateSignature)
+		if err != nil || !isValid {
+			t.Fatalf("%v: MinSigFastAggregateVerify failed: %v", curve, err)
+		}
+
+		verifier, err := NewMinSigPrecomputedVerifier(*params, publicKeys[0])
+		if err != nil {
+			t.Fatalf("NewMinSigPrecomputedVerifier failed: %v", err)
+		}
+		isValid, err = verifier.Verify(signatures[0])
+		if err != nil || !isValid {
+			t.Fatalf("%v: MinSigPrecomputedVerifier failed: %v", curve, err)
+		}
+		isValid, err = verifier.Verify(signatures[1])
+		if err != nil || isValid {
+			t.Fatalf("%v: MinSigPrecomputedVerifier accepted a signature under another key", curve)
+		}
+	}
+}
+
+// TestCurveThresholdAndBatch 测试所有曲线上的门限签名、批量验证与预计算验证。
+func TestCurveThresholdAndBatch(t *testing.T) {
+	message := []byte("threshold message")
+	for _, curve := range SupportedCurves() {
+		params, err := SetUpOnCurve(curve)
+		if err != nil {
+			t.Fatalf("SetUpOnCurve failed: %v", err)
+		}
+		groupKeyPair, shares, err := ThresholdKeyGeneration(*params, 3, 5)
+		if err != nil {
+			t.Fatalf("ThresholdKeyGeneration failed: %v", err)
+		}
+		var partialSignatures []PartialSignature
+		for _, share := range shares[1:4] {
+			partialSignature, err := PartialSign(*params, share, message)
+			if err != nil {
+				t.Fatalf("PartialSign failed: %v", err)
+			}
+			isValid, err := PartialVerify(*params, share.PublicKey, message, *partialSignature)
+			if err != nil || !isValid {
+				t.Fatalf("%v: PartialVerify failed: %v", curve, err)
+			}
+			partialSignatures = append(partialSignatures, *partialSignature)
+		}
+		signature, err := CombinePartialSignatures(*params, 3, message, partialSignatures)
+		if err != nil {
+			t.Fatalf("CombinePartialSignatures failed: %v", err)
+		}
+		direct, err := Sign(*params, groupKeyPair.PrivateKey, message)
+		if err != nil {
+			t.Fatalf("Sign failed: %v", err)
+		}
+		if !signature.Signature.Equal(direct.Signature) {
+			t.Fatalf("%v: combined signature does not match the group signature", curve)
+		}
+
+		publicKeys := []G1Point{groupKeyPair.PublicKey, shares[0].PublicKey, groupKeyPair.PublicKey}
+		signatures := []BLSSignature{*signature, *signature, *direct}
+		isValid, invalid, err := BatchVerify(*params, publicKeys, signatures, 2)
+		if err != nil || isValid || len(invalid) != 1 || invalid[0] != 1 {
+			t.Fatalf("%v: BatchVerify returned %v, %v, %v", curve, isValid, invalid, err)
+		}
+
+		verifier, err := NewPrecomputedVerifier(*params, message)
+		if err != nil {
+			t.Fatalf("NewPrecomputedVerifier failed: %v", err)
+		}
+		isValid, err = verifier.Verify(groupKeyPair.PublicKey, signature.Signature)
+		if err != nil || !isValid {
+			t.Fatalf("%v: PrecomputedVerifier failed: %v", curve, err)
+		}
+		isValid, err = verifier.Verify(shares[0].PublicKey, signature.Signature)
+		if err != nil || isValid {
+			t.Fatalf("%v: PrecomputedVerifier accepted a signature under another key", curve)
+		}
+	}
+}
+
+// TestCurveEthereumVector 测试BLS12-381上与以太坊共识层规范测试用例(sign_case)的一致性。
+func TestCurveEthereumVector(t *testing.T) {
+	params, err := SetUpOnCurveWithCiphersuite(ecc.BLS12_381, CiphersuiteProofOfPossession)
+	if err != nil {
+		t.Fatalf("SetUpOnCurveWithCiphersuite failed: %v", err)
+	}
+	privateKey, _ := new(big.Int).SetString("263dbd792f5b1be47ed85f8938c0f29586af0d3ac7b977f21c278fe1462040e3", 16)
+	expectedPublicKey := "a491d1b0ecd9bb917989f0e74f0dea0422eac4a873e5e2644f368dffb9a6e20fd6e10c1b77654d067c0618f6e5a7f79a"
+	expectedSignature := "b6ed936746e01f8ecf281f020953fbf1f01debd5657c4a383940b020b26507f6076334f91e2366c96e9ab279fb5158090352ea1c5b0c9274504f4f0e7053af24802e51e4568d164fe986834f41e55c8e850ce1f98458c0cfc9ab380b55285a55"
+
+	publicKey := SkToPk(*params, privateKey)
+	if hex.EncodeToString(publicKey.Bytes()) != expectedPublicKey {
+		t.Fatalf("public key = %x, expected %s", publicKey.Bytes(), expectedPublicKey)
+	}
+	signature, err := Sign(*params, privateKey, make([]byte, 32))
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+	if hex.EncodeToString(signature.Signature.Bytes()) != expectedSignature {
+		t.Fatalf("signature = %x, expected %s", signature.Signature.Bytes(), expectedSignature)
+	}
+	// 点可以取回gnark-crypto的类型
+	if _, ok := signature.Signature.Affine().(bls12381.G2Affine); !ok {
+		t.Fatalf("unexpected affine type %T", signature.Signature.Affine())
+	}
+
+	// EIP-2333测试用例0
+	seed, _ := hex.DecodeString("c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04")
+	child, err := DerivePath(*params, seed, "m/0")
+	if err != nil {
+		t.Fatalf("DerivePath failed: %v", err)
+	}
+	if child.String() != "20397789859736650942317412262472558107875392172444076792671091975210932703118" {
+		t.Fatalf("unexpected derived key %v", child)
+	}
+}
+
+// TestCurveBN254Compatibility 测试默认参数与显式指定BN254的参数相同,且点与bn254类型互通。
+func TestCurveBN254Compatibility(t *testing.T) {
+	params, err := SetUp()
+	if err != nil {
+		t.Fatalf("SetUp failed: %v", err)
+	}
+	explicit, err := SetUpOnCurve(ecc.BN254)
+	if err != nil {
+		t.Fatalf("SetUpOnCurve failed: %v", err)
+	}
+	if !params.G1Generator.Equal(explicit.G1Generator) || !params.G2Generator.Equal(explicit.G2Generator) || string(params.DST) != string(explicit.DST) {
+		t.Fatal("SetUp and SetUpOnCurve(BN254) returned different params")
+	}
+	keyPair, err := KeyGeneration(*params)
+	if err != nil {
+		t.Fatalf("KeyGeneration failed: %v", err)
+	}
+	publicKey, ok := keyPair.PublicKey.Affine().(bn254.G1Affine)
+	if !ok {
+		t.Fatalf("unexpected affine type %T", keyPair.PublicKey.Affine())
+	}
+	if !NewG1Point(publicKey).Equal(keyPair.PublicKey) {
+		t.Fatal("NewG1Point does not round-trip the public key")
+	}
+	encoded := publicKey.Bytes()
+	if string(encoded[:]) != string(keyPair.PublicKey.Bytes()) {
+		t.Fatal("the compressed encoding differs from gnark-crypto")
+	}
+}
+
+// TestCurveInvalid 测试曲线与参数不匹配、无效公钥等错误情况。
+func TestCurveInvalid(t *testing.T) {
+	if _, err := SetUpOnCurve(ecc.SECP256K1); err == nil {
+		t.Fatal("SetUpOnCurve was expected to reject an unsupported curve")
+	}
+	params, err := SetUpOnCurve(ecc.BLS12_381)
+	if err != nil {
+		t.Fatalf("SetUpOnCurve failed: %v", err)
+	}
+	bn254Params, err := SetUp()
+	if err != nil {
+		t.Fatalf("SetUp failed: %v", err)
+	}
+	keyPair, err := KeyGeneration(*params)
+	if err != nil {
+		t.Fatalf("KeyGeneration failed: %v", err)
+	}
+	signature, err := Sign(*params, keyPair.PrivateKey, []byte("m"))
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+
+	// 参数的曲线与生成元不一致
+	mixed := *params
+	mixed.G1Generator = bn254Params.G1Generator
+	if _, err := KeyGeneration(mixed); err == nil {
+		t.Fatal("KeyGeneration was expected to reject generators from another curve")
+	}
+	// 其他曲线上的点
+	_, err = Verify(*bn254Params, keyPair.PublicKey, *signature)
+	if !errors.Is(err, ErrCurveMismatch) {
+		t.Fatalf("Verify returned %v, expected ErrCurveMismatch", err)
+	}
+	bn254Signature, err := Sign(*bn254Params, big.NewInt(1), []byte("m"))
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+	if _, err := Aggregate([]BLSSignature{*signature, *bn254Signature}); !errors.Is(err, ErrCurveMismatch) {
+		t.Fatalf("Aggregate returned %v, expected ErrCurveMismatch", err)
+	}
+	if KeyValidate(*params, G1Point{}) {
+		t.Fatal("KeyValidate accepted the zero value")
+	}
+
+	// 无穷远点的压缩编码
+	infinity := make([]byte, 48)
+	infinity[0] = 0xc0
+	if _, err := UnmarshalPublicKey(*params, infinity); !errors.Is(err, ErrPointAtInfinity) {
+		t.Fatalf("UnmarshalPublicKey returned %v, expected ErrPointAtInfinity", err)
+	}
+	if _, err := UnmarshalPublicKey(*params, keyPair.PublicKey.Bytes()[:47]); !errors.Is(err, ErrInvalidLength) {
+		t.Fatalf("UnmarshalPublicKey returned %v, expected ErrInvalidLength", err)
+	}
+	if _, err := Sign(*params, big.NewInt(0), []byte("m")); err == nil {
+		t.Fatal("Sign was expected to reject a zero private key")
+	}
+}
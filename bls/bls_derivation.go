@@ -0,0 +1,152 @@
+package bls
+
+// 参考规范:
+// EIP-2333: BLS12-381 Key Generation. https://eips.ethereum.org/EIPS/eip-2333
+// EIP-2334: BLS12-381 Deterministic Account Hierarchy. https://eips.ethereum.org/EIPS/eip-2334
+//
+// 派生过程与EIP-2333完全相同,HKDF_mod_r中的模数r为blsParams.Curve的标量域阶(BLS12-381上与EIP-2333一致):
+//   - 主私钥: master_SK = HKDF_mod_r(seed)
+//   - 子私钥: child_SK = HKDF_mod_r(compressed_lamport_PK(parent_SK, index))
+//
+// 子私钥的派生经过一次性Lamport公钥,即使parent_SK被量子计算机破解的公钥泄露,子私钥仍然安全。
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"github.com/mmsyan/GnarkPairingProject/utils"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+const (
+	lamportChunks    = 255
+	lamportChunkSize = sha256.Size
+)
+
+// DeriveMasterSK 由至少32字节的种子派生主私钥。
+func DeriveMasterSK(blsParams BLSParams, seed []byte) (*big.Int, error) {
+	backend, err := backendFor(curveOf(blsParams))
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive master key: %v", err)
+	}
+	return deriveMasterSK(seed, backend.curve().ScalarField())
+}
+
+// DeriveChildSK 由父私钥派生下标为index的子私钥。
+func DeriveChildSK(blsParams BLSParams, parentSK *big.Int, index uint32) (*big.Int, error) {
+	backend, err := backendFor(curveOf(blsParams))
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive child key: %v", err)
+	}
+	return deriveChildSK(parentSK, index, backend.curve().ScalarField())
+}
+
+// DerivePath 由种子沿路径派生私钥,路径格式为"m/12381/3600/0/0",
+// "m"表示主私钥,其后每一级为十进制的子私钥下标。
+func DerivePath(blsParams BLSParams, seed []byte, path string) (*big.Int, error) {
+	backend, err := backendFor(curveOf(blsParams))
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %v", err)
+	}
+	return derivePath(seed, path, backend.curve().ScalarField())
+}
+
+// DeriveKeyPath 由种子沿路径派生密钥对。
+func DeriveKeyPath(blsParams BLSParams, seed []byte, path string) (*BLSKeyPair, error) {
+	sk, err := DerivePath(blsParams, seed, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key pair: %v", err)
+	}
+	return &BLSKeyPair{
+		PrivateKey: sk,
+		PublicKey:  SkToPk(blsParams, sk),
+	}, nil
+}
+
+// ParsePath 解析"m/a/b/c"形式的派生路径,返回各级子私钥的下标。
+func ParsePath(path string) ([]uint32, error) {
+	parts := strings.Split(path, "/")
+	if parts[0] != "m" {
+		return nil, fmt.Errorf("invalid derivation path %q: must start with m", path)
+	}
+	indices := make([]uint32, 0, len(parts)-1)
+	for _, part := range parts[1:] {
+		index, err := strconv.ParseUint(part, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid derivation path %q: %v", path, err)
+		}
+		indices = append(indices, uint32(index))
+	}
+	return indices, nil
+}
+
+func derivePath(seed []byte, path string, r *big.Int) (*big.Int, error) {
+	indices, err := ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	sk, err := deriveMasterSK(seed, r)
+	if err != nil {
+		return nil, err
+	}
+	for _, index := range indices {
+		sk, err = deriveChildSK(sk, index, r)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return sk, nil
+}
+
+func deriveMasterSK(seed []byte, r *big.Int) (*big.Int, error) {
+	if len(seed) < 32 {
+		return nil, fmt.Errorf("failed to derive master key: seed must be at least 32 bytes")
+	}
+	return hkdfModR(seed, nil, r)
+}
+
+func deriveChildSK(parentSK *big.Int, index uint32, r *big.Int) (*big.Int, error) {
+	if parentSK == nil || parentSK.Sign() < 0 || parentSK.Cmp(r) >= 0 {
+		return nil, fmt.Errorf("failed to derive child key: invalid parent key")
+	}
+	compressedLamportPK, err := parentSKToLamportPK(parentSK, index, r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive child key: %v", err)
+	}
+	return hkdfModR(compressedLamportPK, nil, r)
+}
+
+// parentSKToLamportPK 计算父私钥在下标index处的压缩Lamport公钥:
+//
+//	salt = I2OSP(index, 4)
+//	IKM = I2OSP(parent_SK, ceil(log2(r) / 8))  (BN254、BLS12-381与BLS12-377为32字节)
+//	lamport_0 = IKM_to_lamport_SK(IKM, salt)
+//	lamport_1 = IKM_to_lamport_SK(flip_bits(IKM), salt)
+//	lamport_PK = SHA256(lamport_0[0]) || ... || SHA256(lamport_1[254])
+//	compressed_lamport_PK = SHA256(lamport_PK)
+func parentSKToLamportPK(parentSK *big.Int, index uint32, r *big.Int) ([]byte, error) {
+	salt := make([]byte, 4)
+	binary.BigEndian.PutUint32(salt, index)
+	ikm := parentSK.FillBytes(make([]byte, (r.BitLen()+7)/8))
+	notIKM := make([]byte, len(ikm))
+	for i := range ikm {
+		notIKM[i] = ^ikm[i]
+	}
+
+	lamportPK := sha256.New()
+	for _, material := range [][]byte{ikm, notIKM} {
+		// IKM_to_lamport_SK: HKDF(salt, IKM, "", 255 * 32)
+		prk := utils.HKDFExtract(salt, material)
+		okm, err := utils.HKDFExpand(prk, nil, lamportChunks*lamportChunkSize)
+		if err != nil {
+			return nil, err
+		}
+		for i := 0; i < lamportChunks; i++ {
+			chunk := sha256.Sum256(okm[i*lamportChunkSize : (i+1)*lamportChunkSize])
+			lamportPK.Write(chunk[:])
+		}
+	}
+	return lamportPK.Sum(nil), nil
+}
@@ -0,0 +1,94 @@
+package bls
+
+import (
+	"encoding/hex"
+	"github.com/consensys/gnark-crypto/ecc"
+	"math/big"
+	"testing"
+)
+
+// TestDeriveEIP2333Vector 使用EIP-2333的测试用例0检查BLS12-381上的派生过程。
+func TestDeriveEIP2333Vector(t *testing.T) {
+	params, err := SetUpOnCurve(ecc.BLS12_381)
+	if err != nil {
+		t.Fatalf("SetUpOnCurve failed: %v", err)
+	}
+	seed, _ := hex.DecodeString("c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04")
+	expectedMaster, _ := new(big.Int).SetString("6083874454709270928345386274498605044986640685124978867557563392430687146096", 10)
+	expectedChild, _ := new(big.Int).SetString("20397789859736650942317412262472558107875392172444076792671091975210932703118", 10)
+
+	master, err := DeriveMasterSK(*params, seed)
+	if err != nil {
+		t.Fatalf("DeriveMasterSK failed: %v", err)
+	}
+	if master.Cmp(expectedMaster) != 0 {
+		t.Fatalf("master key = %v, expected %v", master, expectedMaster)
+	}
+	child, err := DeriveChildSK(*params, master, 0)
+	if err != nil {
+		t.Fatalf("DeriveChildSK failed: %v", err)
+	}
+	if child.Cmp(expectedChild) != 0 {
+		t.Fatalf("child key = %v, expected %v", child, expectedChild)
+	}
+}
+
+// TestDerivePath 测试按路径派生的确定性,以及派生出的密钥对可以正常签名。
+func TestDerivePath(t *testing.T) {
+	params, err := SetUp()
+	if err != nil {
+		t.Fatalf("SetUp failed: %v", err)
+	}
+	seed := make([]byte, 32)
+	for i := range seed {
+		seed[i] = byte(i)
+	}
+
+	sk, err := DerivePath(*params, seed, "m/12381/3600/0/0")
+	if err != nil {
+		t.Fatalf("DerivePath failed: %v", err)
+	}
+	// 逐级派生应得到相同的结果
+	expected, err := DeriveMasterSK(*params, seed)
+	if err != nil {
+		t.Fatalf("DeriveMasterSK failed: %v", err)
+	}
+	for _, index := range []uint32{12381, 3600, 0, 0} {
+		if expected, err = DeriveChildSK(*params, expected, index); err != nil {
+			t.Fatalf("DeriveChildSK failed: %v", err)
+		}
+	}
+	if sk.Cmp(expected) != 0 {
+		t.Fatal("DerivePath does not match step-by-step derivation")
+	}
+	other, err := DerivePath(*params, seed, "m/12381/3600/1/0")
+	if err != nil {
+		t.Fatalf("DerivePath failed: %v", err)
+	}
+	if sk.Cmp(other) == 0 {
+		t.Fatal("different paths derived the same key")
+	}
+
+	keyPair, err := DeriveKeyPath(*params, seed, "m/12381/3600/0/0")
+	if err != nil {
+		t.Fatalf("DeriveKeyPath failed: %v", err)
+	}
+	message := []byte("derived key")
+	signature, err := Sign(*params, keyPair.PrivateKey, message)
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+	isValid, err := Verify(*params, keyPair.PublicKey, *signature)
+	if err != nil || !isValid {
+		t.Fatalf("Verify failed: %v", err)
+	}
+
+	for _, path := range []string{"", "n/0", "m/", "m/-1", "m/4294967296", "m/0/x"} {
+		if _, err := DerivePath(*params, seed, path); err == nil {
+			t.Fatalf("DerivePath was expected to reject path %q", path)
+		}
+	}
+	if _, err := DeriveMasterSK(*params, seed[:31]); err == nil {
+		t.Fatal("DeriveMasterSK was expected to reject seeds shorter than 32 bytes")
+	}
+}
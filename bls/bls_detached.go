@@ -0,0 +1,182 @@
+package bls
+
+// 分离式签名与流式签名
+//
+// BLSSignature携带被签名的消息,不适合对大文件签名。该文件提供:
+//   - 分离式签名: 签名只包含G2上的点h(m)^x,验证时由调用者提供消息
+//   - 先哈希后签名: digest = SHA-256(m),签名为h'(digest)^x,
+//     其中h'使用与普通签名不同的域分隔标签BLSParams.PrehashDST,
+//     因此先哈希后签名的签名不能被当作对消息digest本身的普通签名
+//   - 流式签名: 从io.Reader中读取消息并计算SHA-256,不需要将消息读入内存
+//
+// MESSAGE-AUGMENTATION方案下,先哈希后签名签的是pk || digest。
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"math/big"
+)
+
+// PrehashSize 为先哈希后签名使用的摘要长度。
+const PrehashSize = sha256.Size
+
+// Signer 表示持有私钥、能够产生分离式签名的对象。
+type Signer interface {
+	// PublicKey 返回与签名私钥对应的公钥。
+	PublicKey() G1Point
+	// Sign 对消息产生分离式签名。
+	Sign(message []byte) (*G2Point, error)
+	// SignReader 对reader中的全部数据产生先哈希后签名的分离式签名。
+	SignReader(reader io.Reader) (*G2Point, error)
+}
+
+// Verifier 表示持有公钥、能够验证分离式签名的对象。
+type Verifier interface {
+	// Verify 验证对消息的分离式签名。
+	Verify(message []byte, signature G2Point) (bool, error)
+	// VerifyReader 验证对reader中全部数据的先哈希后签名的分离式签名。
+	VerifyReader(reader io.Reader, signature G2Point) (bool, error)
+}
+
+// KeySigner 是使用内存中私钥实现的Signer。
+type KeySigner struct {
+	blsParams  BLSParams
+	privateKey *big.Int
+	publicKey  G1Point
+}
+
+// KeyVerifier 是使用公钥实现的Verifier。
+type KeyVerifier struct {
+	blsParams BLSParams
+	publicKey G1Point
+}
+
+var (
+	_ Signer   = (*KeySigner)(nil)
+	_ Verifier = (*KeyVerifier)(nil)
+)
+
+// NewKeySigner 由私钥创建Signer。
+func NewKeySigner(blsParams BLSParams, privateKey *big.Int) (*KeySigner, error) {
+	backend, err := backendOf(blsParams, VariantMinPublicKeySize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create signer: %v", err)
+	}
+	if err := checkPrivateKey(backend, privateKey); err != nil {
+		return nil, fmt.Errorf("failed to create signer: %w", err)
+	}
+	return &KeySigner{
+		blsParams:  blsParams,
+		privateKey: new(big.Int).Set(privateKey),
+		publicKey:  SkToPk(blsParams, privateKey),
+	}, nil
+}
+
+// NewKeyVerifier 由公钥创建Verifier,公钥必须通过ValidatePublicKey检查。
+func NewKeyVerifier(blsParams BLSParams, publicKey G1Point) (*KeyVerifier, error) {
+	if _, err := backendOf(blsParams, VariantMinPublicKeySize); err != nil {
+		return nil, fmt.Errorf("failed to create verifier: %v", err)
+	}
+	if err := ValidatePublicKey(blsParams, publicKey); err != nil {
+		return nil, fmt.Errorf("failed to create verifier: %w", err)
+	}
+	return &KeyVerifier{
+		blsParams: blsParams,
+		publicKey: publicKey,
+	}, nil
+}
+
+func (signer *KeySigner) PublicKey() G1Point {
+	return signer.publicKey
+}
+
+func (signer *KeySigner) Sign(message []byte) (*G2Point, error) {
+	return DetachedSign(signer.blsParams, signer.privateKey, message)
+}
+
+func (signer *KeySigner) SignReader(reader io.Reader) (*G2Point, error) {
+	return SignReader(signer.blsParams, signer.privateKey, reader)
+}
+
+func (verifier *KeyVerifier) Verify(message []byte, signature G2Point) (bool, error) {
+	return DetachedVerify(verifier.blsParams, verifier.publicKey, message, signature)
+}
+
+func (verifier *KeyVerifier) VerifyReader(reader io.Reader, signature G2Point) (bool, error) {
+	return VerifyReader(verifier.blsParams, verifier.publicKey, reader, signature)
+}
+
+// DetachedSign 对消息产生分离式签名h(m)^x,与Sign返回的签名点相同,但不携带消息。
+func DetachedSign(blsParams BLSParams, privateKey *big.Int, message []byte) (*G2Point, error) {
+	signature, err := signWithDST(blsParams, privateKey, message, blsParams.DST)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign message: %v", err)
+	}
+	return signature, nil
+}
+
+// DetachedVerify 验证对消息的分离式签名。
+func DetachedVerify(blsParams BLSParams, publicKey G1Point, message []byte, signature G2Point) (bool, error) {
+	isValid, err := verifyWithDST(blsParams, publicKey, message, signature, blsParams.DST)
+	if err != nil {
+		return false, fmt.Errorf("failed to verify signature: %w", err)
+	}
+	return isValid, nil
+}
+
+// SignPrehashed 对SHA-256摘要产生先哈希后签名的分离式签名,h'使用PrehashDST。
+func SignPrehashed(blsParams BLSParams, privateKey *big.Int, digest []byte) (*G2Point, error) {
+	if len(digest) != PrehashSize {
+		return nil, fmt.Errorf("failed to sign digest: digest must be %d bytes, got %d", PrehashSize, len(digest))
+	}
+	if len(blsParams.PrehashDST) == 0 {
+		return nil, fmt.Errorf("failed to sign digest: params have no prehash DST")
+	}
+	signature, err := signWithDST(blsParams, privateKey, digest, blsParams.PrehashDST)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign digest: %v", err)
+	}
+	return signature, nil
+}
+
+// VerifyPrehashed 验证对SHA-256摘要的先哈希后签名的分离式签名。
+func VerifyPrehashed(blsParams BLSParams, publicKey G1Point, digest []byte, signature G2Point) (bool, error) {
+	if len(digest) != PrehashSize {
+		return false, fmt.Errorf("failed to verify signature: digest must be %d bytes, got %d", PrehashSize, len(digest))
+	}
+	if len(blsParams.PrehashDST) == 0 {
+		return false, fmt.Errorf("failed to verify signature: params have no prehash DST")
+	}
+	isValid, err := verifyWithDST(blsParams, publicKey, digest, signature, blsParams.PrehashDST)
+	if err != nil {
+		return false, fmt.Errorf("failed to verify signature: %w", err)
+	}
+	return isValid, nil
+}
+
+// SignReader 读取reader中的全部数据并计算SHA-256摘要,然后通过SignPrehashed签名。
+func SignReader(blsParams BLSParams, privateKey *big.Int, reader io.Reader) (*G2Point, error) {
+	digest, err := prehash(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign message: %v", err)
+	}
+	return SignPrehashed(blsParams, privateKey, digest)
+}
+
+// VerifyReader 读取reader中的全部数据并计算SHA-256摘要,然后通过VerifyPrehashed验证签名。
+func VerifyReader(blsParams BLSParams, publicKey G1Point, reader io.Reader, signature G2Point) (bool, error) {
+	digest, err := prehash(reader)
+	if err != nil {
+		return false, fmt.Errorf("failed to verify signature: %v", err)
+	}
+	return VerifyPrehashed(blsParams, publicKey, digest, signature)
+}
+
+func prehash(reader io.Reader) ([]byte, error) {
+	h := sha256.New()
+	if _, err := io.Copy(h, reader); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
@@ -0,0 +1,153 @@
+package bls
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"testing"
+)
+
+// zeroReader 产生无限长的0字节流,用于模拟大文件。
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 0
+	}
+	return len(p), nil
+}
+
+// TestDetachedSign 测试分离式签名与Sign产生相同的签名点。
+func TestDetachedSign(t *testing.T) {
+	for _, suite := range []Ciphersuite{CiphersuiteNone, CiphersuiteBasic, CiphersuiteMessageAugmentation} {
+		params, err := SetUpWithCiphersuite(suite)
+		if suite == CiphersuiteNone {
+			params, err = SetUp()
+		}
+		if err != nil {
+			t.Fatalf("SetUp failed: %v", err)
+		}
+		keyPair, err := KeyGeneration(*params)
+		if err != nil {
+			t.Fatalf("KeyGeneration failed: %v", err)
+		}
+		message := []byte("detached")
+		signature, err := DetachedSign(*params, keyPair.PrivateKey, message)
+		if err != nil {
+			t.Fatalf("DetachedSign failed: %v", err)
+		}
+		blsSignature, err := Sign(*params, keyPair.PrivateKey, message)
+		if err != nil {
+			t.Fatalf("Sign failed: %v", err)
+		}
+		if !signature.Equal(blsSignature.Signature) {
+			t.Fatalf("%v: DetachedSign and Sign produced different signatures", suite)
+		}
+		isValid, err := DetachedVerify(*params, keyPair.PublicKey, message, *signature)
+		if err != nil || !isValid {
+			t.Fatalf("%v: DetachedVerify failed: %v", suite, err)
+		}
+		isValid, err = DetachedVerify(*params, keyPair.PublicKey, []byte("other"), *signature)
+		if err != nil || isValid {
+			t.Fatalf("%v: DetachedVerify accepted a different message", suite)
+		}
+	}
+}
+
+// TestSignReader 测试流式签名,以及先哈希后签名与普通签名的域分隔。
+func TestSignReader(t *testing.T) {
+	params, err := SetUpWithCiphersuite(CiphersuiteProofOfPossession)
+	if err != nil {
+		t.Fatalf("SetUpWithCiphersuite failed: %v", err)
+	}
+	keyPair, err := KeyGeneration(*params)
+	if err != nil {
+		t.Fatalf("KeyGeneration failed: %v", err)
+	}
+	const size = 16 << 20
+	signature, err := SignReader(*params, keyPair.PrivateKey, io.LimitReader(zeroReader{}, size))
+	if err != nil {
+		t.Fatalf("SignReader failed: %v", err)
+	}
+	isValid, err := VerifyReader(*params, keyPair.PublicKey, io.LimitReader(zeroReader{}, size), *signature)
+	if err != nil || !isValid {
+		t.Fatalf("VerifyReader failed: %v", err)
+	}
+	isValid, err = VerifyReader(*params, keyPair.PublicKey, io.LimitReader(zeroReader{}, size+1), *signature)
+	if err != nil || isValid {
+		t.Fatal("VerifyReader accepted a different stream")
+	}
+
+	// 流式签名等价于对SHA-256摘要的先哈希后签名,但不是对摘要的普通签名
+	message := []byte("small message")
+	digest := sha256.Sum256(message)
+	streamed, err := SignReader(*params, keyPair.PrivateKey, bytes.NewReader(message))
+	if err != nil {
+		t.Fatalf("SignReader failed: %v", err)
+	}
+	prehashed, err := SignPrehashed(*params, keyPair.PrivateKey, digest[:])
+	if err != nil {
+		t.Fatalf("SignPrehashed failed: %v", err)
+	}
+	if !streamed.Equal(*prehashed) {
+		t.Fatal("SignReader and SignPrehashed produced different signatures")
+	}
+	isValid, err = DetachedVerify(*params, keyPair.PublicKey, digest[:], *prehashed)
+	if err != nil || isValid {
+		t.Fatal("a prehashed signature was accepted as a plain signature on the digest")
+	}
+	if _, err := SignPrehashed(*params, keyPair.PrivateKey, message); err == nil {
+		t.Fatal("SignPrehashed was expected to reject a digest of the wrong length")
+	}
+}
+
+// TestSignerVerifier 测试Signer和Verifier接口的实现。
+func TestSignerVerifier(t *testing.T) {
+	params, err := SetUp()
+	if err != nil {
+		t.Fatalf("SetUp failed: %v", err)
+	}
+	keyPair, err := KeyGeneration(*params)
+	if err != nil {
+		t.Fatalf("KeyGeneration failed: %v", err)
+	}
+	var signer Signer
+	signer, err = NewKeySigner(*params, keyPair.PrivateKey)
+	if err != nil {
+		t.Fatalf("NewKeySigner failed: %v", err)
+	}
+	publicKey := signer.PublicKey()
+	var verifier Verifier
+	verifier, err = NewKeyVerifier(*params, publicKey)
+	if err != nil {
+		t.Fatalf("NewKeyVerifier failed: %v", err)
+	}
+
+	message := []byte("through the interfaces")
+	signature, err := signer.Sign(message)
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+	if isValid, err := verifier.Verify(message, *signature); err != nil || !isValid {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	signature, err = signer.SignReader(bytes.NewReader(message))
+	if err != nil {
+		t.Fatalf("SignReader failed: %v", err)
+	}
+	if isValid, err := verifier.VerifyReader(bytes.NewReader(message), *signature); err != nil || !isValid {
+		t.Fatalf("VerifyReader failed: %v", err)
+	}
+
+	minSigParams, err := SetUpMinSig()
+	if err != nil {
+		t.Fatalf("SetUpMinSig failed: %v", err)
+	}
+	if _, err := NewKeySigner(*minSigParams, keyPair.PrivateKey); err == nil {
+		t.Fatal("NewKeySigner was expected to reject min-signature-size params")
+	}
+	if _, err := NewKeySigner(*params, nil); !errors.Is(err, ErrInvalidPrivateKey) {
+		t.Fatalf("NewKeySigner was expected to reject a nil private key, got %v", err)
+	}
+}
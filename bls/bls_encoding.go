@@ -0,0 +1,205 @@
+package bls
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// 编码格式(括号内为BN254上的长度,其他曲线见bls_curve.go):
+//   - 私钥: 与r等长的大端整数(32字节),取值范围为[1, r)
+//   - G1上的点(默认变体的公钥、签名位于G1的变体的签名): 压缩(32字节)或未压缩(64字节)
+//   - G2上的点(默认变体的签名、签名位于G1的变体的公钥): 压缩(64字节)或未压缩(128字节)
+//
+// 点的编码与gnark-crypto的Bytes()/RawBytes()一致,最高两位比特标记压缩方式。
+// 解码时按BLSParams.Curve选择曲线。
+// 签名只编码G2(或G1)上的点本身,不包含被签名的消息(分离式签名)。
+
+// 解码错误的类别,可以通过errors.Is判断。
+var (
+	ErrInvalidLength     = errors.New("invalid encoding length")
+	ErrInvalidEncoding   = errors.New("invalid encoding")
+	ErrPointAtInfinity   = errors.New("point at infinity")
+	ErrNotOnCurve        = errors.New("point is not on the curve")
+	ErrNotInSubgroup     = errors.New("point is not in the prime-order subgroup")
+	ErrInvalidPrivateKey = errors.New("private key is out of range")
+)
+
+// DecodeError 表示解码私钥、公钥或签名失败,Object为被解码对象的名称,Err为上面定义的错误类别之一。
+type DecodeError struct {
+	Object string
+	Err    error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("failed to decode %s: %v", e.Object, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationError 表示私钥、公钥或签名未通过有效性检查,Object为被检查对象的名称,
+// Err为ErrInvalidPrivateKey、ErrCurveMismatch、ErrPointAtInfinity、ErrNotOnCurve或ErrNotInSubgroup之一。
+type ValidationError struct {
+	Object string
+	Err    error
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid %s: %v", e.Object, e.Err)
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// ValidatePrivateKey 检查私钥位于参数曲线标量域的[1, r)中。
+func ValidatePrivateKey(blsParams BLSParams, privateKey *big.Int) error {
+	backend, err := backendFor(curveOf(blsParams))
+	if err != nil {
+		return fmt.Errorf("failed to validate private key: %w", err)
+	}
+	if err := checkPrivateKey(backend, privateKey); err != nil {
+		return &ValidationError{Object: "private key", Err: err}
+	}
+	return nil
+}
+
+// ValidatePublicKey 检查公钥在参数曲线的G1上、位于素数阶子群中且不是无穷远点。
+func ValidatePublicKey(blsParams BLSParams, publicKey G1Point) error {
+	if err := validatePoint(curveOf(blsParams), publicKey.p); err != nil {
+		return &ValidationError{Object: "public key", Err: err}
+	}
+	return nil
+}
+
+// ValidateSignature 检查签名在参数曲线的G2上、位于素数阶子群中且不是无穷远点。
+func ValidateSignature(blsParams BLSParams, signature G2Point) error {
+	if err := validatePoint(curveOf(blsParams), signature.p); err != nil {
+		return &ValidationError{Object: "signature", Err: err}
+	}
+	return nil
+}
+
+// ValidateMinSigPublicKey 检查签名位于G1的变体的G2公钥。
+func ValidateMinSigPublicKey(blsParams BLSParams, publicKey G2Point) error {
+	if err := validatePoint(curveOf(blsParams), publicKey.p); err != nil {
+		return &ValidationError{Object: "public key", Err: err}
+	}
+	return nil
+}
+
+// ValidateMinSigSignature 检查签名位于G1的变体的G1签名。
+func ValidateMinSigSignature(blsParams BLSParams, signature G1Point) error {
+	if err := validatePoint(curveOf(blsParams), signature.p); err != nil {
+		return &ValidationError{Object: "signature", Err: err}
+	}
+	return nil
+}
+
+// PrivateKeySize 返回参数曲线上私钥编码的字节数,即标量域的阶r的字节数。
+func PrivateKeySize(blsParams BLSParams) int {
+	return (curveOf(blsParams).ScalarField().BitLen() + 7) / 8
+}
+
+// MarshalPrivateKey 将私钥编码为PrivateKeySize字节的大端整数。
+func MarshalPrivateKey(blsParams BLSParams, privateKey *big.Int) ([]byte, error) {
+	backend, err := backendFor(curveOf(blsParams))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode private key: %w", err)
+	}
+	if err := checkPrivateKey(backend, privateKey); err != nil {
+		return nil, fmt.Errorf("failed to encode private key: %w", err)
+	}
+	return privateKey.FillBytes(make([]byte, PrivateKeySize(blsParams))), nil
+}
+
+// UnmarshalPrivateKey 解码PrivateKeySize字节大端整数形式的私钥,拒绝0和大于等于r的值。
+func UnmarshalPrivateKey(blsParams BLSParams, data []byte) (*big.Int, error) {
+	backend, err := backendFor(curveOf(blsParams))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode private key: %w", err)
+	}
+	if len(data) != PrivateKeySize(blsParams) {
+		return nil, &DecodeError{Object: "private key", Err: ErrInvalidLength}
+	}
+	privateKey := new(big.Int).SetBytes(data)
+	if err := checkPrivateKey(backend, privateKey); err != nil {
+		return nil, &DecodeError{Object: "private key", Err: err}
+	}
+	return privateKey, nil
+}
+
+// MarshalPublicKey 编码默认变体的G1公钥,compressed为true时输出压缩编码,否则输出两倍长度的未压缩编码。
+func MarshalPublicKey(publicKey G1Point, compressed bool) []byte {
+	return marshalPoint(publicKey.p, compressed)
+}
+
+// UnmarshalPublicKey 解码参数曲线上默认变体的G1公钥(压缩或未压缩),检查点在曲线上、位于子群中且不是无穷远点。
+func UnmarshalPublicKey(blsParams BLSParams, data []byte) (*G1Point, error) {
+	p, err := unmarshalPoint(blsParams, "public key", data, backendGroup1)
+	if err != nil {
+		return nil, err
+	}
+	return &G1Point{p}, nil
+}
+
+// MarshalSignature 编码默认变体的分离式G2签名,compressed为true时输出压缩编码,否则输出未压缩编码。
+func MarshalSignature(signature G2Point, compressed bool) []byte {
+	return marshalPoint(signature.p, compressed)
+}
+
+// UnmarshalSignature 解码参数曲线上默认变体的分离式G2签名(压缩或未压缩),检查点在曲线上、位于子群中且不是无穷远点。
+func UnmarshalSignature(blsParams BLSParams, data []byte) (*G2Point, error) {
+	p, err := unmarshalPoint(blsParams, "signature", data, backendGroup2)
+	if err != nil {
+		return nil, err
+	}
+	return &G2Point{p}, nil
+}
+
+// MarshalMinSigPublicKey 编码签名位于G1的变体的G2公钥。
+func MarshalMinSigPublicKey(publicKey G2Point, compressed bool) []byte {
+	return marshalPoint(publicKey.p, compressed)
+}
+
+// UnmarshalMinSigPublicKey 解码签名位于G1的变体的G2公钥。
+func UnmarshalMinSigPublicKey(blsParams BLSParams, data []byte) (*G2Point, error) {
+	p, err := unmarshalPoint(blsParams, "public key", data, backendGroup2)
+	if err != nil {
+		return nil, err
+	}
+	return &G2Point{p}, nil
+}
+
+// MarshalMinSigSignature 编码签名位于G1的变体的分离式G1签名。
+func MarshalMinSigSignature(signature G1Point, compressed bool) []byte {
+	return marshalPoint(signature.p, compressed)
+}
+
+// UnmarshalMinSigSignature 解码签名位于G1的变体的分离式G1签名。
+func UnmarshalMinSigSignature(blsParams BLSParams, data []byte) (*G1Point, error) {
+	p, err := unmarshalPoint(blsParams, "signature", data, backendGroup1)
+	if err != nil {
+		return nil, err
+	}
+	return &G1Point{p}, nil
+}
+
+func backendGroup1(backend curveBackend) group {
+	return backend.g1()
+}
+
+func backendGroup2(backend curveBackend) group {
+	return backend.g2()
+}
+
+// unmarshalPoint 在参数曲线的G1或G2上解码点,曲线不受支持时返回错误。
+func unmarshalPoint(blsParams BLSParams, object string, data []byte, groupOf func(curveBackend) group) (point, error) {
+	backend, err := backendFor(curveOf(blsParams))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", object, err)
+	}
+	return groupOf(backend).unmarshal(object, data)
+}
@@ -0,0 +1,273 @@
+package bls
+
+import (
+	"errors"
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"math/big"
+	"testing"
+)
+
+// TestEncodingRoundTrip 测试私钥、公钥和分离式签名的压缩与未压缩编码。
+func TestEncodingRoundTrip(t *testing.T) {
+	params, err := SetUp()
+	if err != nil {
+		t.Fatalf("SetUp failed: %v", err)
+	}
+	keyPair, err := KeyGeneration(*params)
+	if err != nil {
+		t.Fatalf("KeyGeneration failed: %v", err)
+	}
+	message := []byte("encoded message")
+	signature, err := Sign(*params, keyPair.PrivateKey, message)
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+
+	encodedPrivateKey, err := MarshalPrivateKey(*params, keyPair.PrivateKey)
+	if err != nil {
+		t.Fatalf("MarshalPrivateKey failed: %v", err)
+	}
+	privateKey, err := UnmarshalPrivateKey(*params, encodedPrivateKey)
+	if err != nil || privateKey.Cmp(keyPair.PrivateKey) != 0 {
+		t.Fatalf("UnmarshalPrivateKey failed: %v", err)
+	}
+
+	for _, compressed := range []bool{true, false} {
+		encodedPublicKey := MarshalPublicKey(keyPair.PublicKey, compressed)
+		encodedSignature := MarshalSignature(signature.Signature, compressed)
+		if compressed && (len(encodedPublicKey) != 32 || len(encodedSignature) != 64) {
+			t.Fatalf("unexpected compressed sizes %d, %d", len(encodedPublicKey), len(encodedSignature))
+		}
+		if !compressed && (len(encodedPublicKey) != 64 || len(encodedSignature) != 128) {
+			t.Fatalf("unexpected uncompressed sizes %d, %d", len(encodedPublicKey), len(encodedSignature))
+		}
+
+		publicKey, err := UnmarshalPublicKey(*params, encodedPublicKey)
+		if err != nil {
+			t.Fatalf("UnmarshalPublicKey failed: %v", err)
+		}
+		decodedSignature, err := UnmarshalSignature(*params, encodedSignature)
+		if err != nil {
+			t.Fatalf("UnmarshalSignature failed: %v", err)
+		}
+		isValid, err := Verify(*params, *publicKey, BLSSignature{Message: message, Signature: *decodedSignature})
+		if err != nil || !isValid {
+			t.Fatalf("Verify failed after decoding: %v", err)
+		}
+	}
+
+	minSigParams, err := SetUpMinSig()
+	if err != nil {
+		t.Fatalf("SetUpMinSig failed: %v", err)
+	}
+	minSigKeyPair, err := MinSigKeyGeneration(*minSigParams)
+	if err != nil {
+		t.Fatalf("MinSigKeyGeneration failed: %v", err)
+	}
+	minSigSignature, err := MinSigSign(*minSigParams, minSigKeyPair.PrivateKey, message)
+	if err != nil {
+		t.Fatalf("MinSigSign failed: %v", err)
+	}
+	minSigPublicKey, err := UnmarshalMinSigPublicKey(*minSigParams, MarshalMinSigPublicKey(minSigKeyPair.PublicKey, true))
+	if err != nil || !minSigPublicKey.Equal(minSigKeyPair.PublicKey) {
+		t.Fatalf("UnmarshalMinSigPublicKey failed: %v", err)
+	}
+	decodedMinSig, err := UnmarshalMinSigSignature(*minSigParams, MarshalMinSigSignature(minSigSignature.Signature, false))
+	if err != nil || !decodedMinSig.Equal(minSigSignature.Signature) {
+		t.Fatalf("UnmarshalMinSigSignature failed: %v", err)
+	}
+}
+
+// TestDecodingRejectsInvalidInput 测试解码时拒绝无效输入,并返回对应的错误类别。
+func TestDecodingRejectsInvalidInput(t *testing.T) {
+	expectError := func(err error, target error) {
+		t.Helper()
+		var decodeError *DecodeError
+		if !errors.As(err, &decodeError) {
+			t.Fatalf("expected a *DecodeError, got %v", err)
+		}
+		if !errors.Is(err, target) {
+			t.Fatalf("expected %v, got %v", target, err)
+		}
+	}
+
+	params, err := SetUp()
+	if err != nil {
+		t.Fatalf("SetUp failed: %v", err)
+	}
+
+	// 私钥: 长度错误、0、大于等于r
+	_, err = UnmarshalPrivateKey(*params, make([]byte, 31))
+	expectError(err, ErrInvalidLength)
+	_, err = UnmarshalPrivateKey(*params, make([]byte, 32))
+	expectError(err, ErrInvalidPrivateKey)
+	_, err = UnmarshalPrivateKey(*params, ecc.BN254.ScalarField().FillBytes(make([]byte, 32)))
+	expectError(err, ErrInvalidPrivateKey)
+
+	// 无穷远点
+	infinityG1 := NewG1Point(bn254.G1Affine{})
+	_, err = UnmarshalPublicKey(*params, MarshalPublicKey(infinityG1, true))
+	expectError(err, ErrPointAtInfinity)
+	infinityG2 := NewG2Point(bn254.G2Affine{})
+	_, err = UnmarshalSignature(*params, MarshalSignature(infinityG2, false))
+	expectError(err, ErrPointAtInfinity)
+
+	// 不在曲线上的点: (1, 1)
+	var offCurve bn254.G1Affine
+	offCurve.X.SetOne()
+	offCurve.Y.SetOne()
+	_, err = UnmarshalPublicKey(*params, MarshalPublicKey(NewG1Point(offCurve), false))
+	expectError(err, ErrNotOnCurve)
+
+	// 在曲线上但不在子群中的G2点: 不做余因子清除的映射结果
+	var u bn254.E2
+	u.A0.SetUint64(7)
+	u.A1.SetUint64(11)
+	notInSubgroup := bn254.MapToCurve2(&u)
+	if !notInSubgroup.IsOnCurve() || notInSubgroup.IsInSubGroup() {
+		t.Fatal("test point was expected to be on the curve but outside the subgroup")
+	}
+	_, err = UnmarshalSignature(*params, MarshalSignature(NewG2Point(notInSubgroup), false))
+	expectError(err, ErrNotInSubgroup)
+
+	// 长度与压缩标记不一致
+	keyPair, _ := KeyGeneration(*params)
+	compressed := MarshalPublicKey(keyPair.PublicKey, true)
+	_, err = UnmarshalPublicKey(*params, append(compressed, make([]byte, 32)...))
+	expectError(err, ErrInvalidLength)
+	_, err = UnmarshalPublicKey(*params, compressed[:31])
+	expectError(err, ErrInvalidLength)
+
+	if _, err := MarshalPrivateKey(*params, big.NewInt(0)); !errors.Is(err, ErrInvalidPrivateKey) {
+		t.Fatalf("MarshalPrivateKey was expected to reject 0, got %v", err)
+	}
+	if err := ValidatePrivateKey(*params, keyPair.PrivateKey); err != nil {
+		t.Fatalf("ValidatePrivateKey failed: %v", err)
+	}
+	var validationError *ValidationError
+	if err := ValidatePrivateKey(*params, params.Curve.ScalarField()); !errors.As(err, &validationError) || !errors.Is(err, ErrInvalidPrivateKey) {
+		t.Fatalf("ValidatePrivateKey was expected to reject r, got %v", err)
+	}
+}
+
+// TestVerifyRejectsInvalidPoints 测试验证函数拒绝无穷远点、不在曲线上和不在子群中的公钥、签名与盲化消息。
+func TestVerifyRejectsInvalidPoints(t *testing.T) {
+	expectError := func(err error, object string, target error) {
+		t.Helper()
+		var validationError *ValidationError
+		if !errors.As(err, &validationError) || validationError.Object != object {
+			t.Fatalf("expected a *ValidationError for %s, got %v", object, err)
+		}
+		if !errors.Is(err, target) {
+			t.Fatalf("expected %v, got %v", target, err)
+		}
+	}
+
+	params, err := SetUpWithCiphersuite(CiphersuiteProofOfPossession)
+	if err != nil {
+		t.Fatalf("SetUpWithCiphersuite failed: %v", err)
+	}
+	keyPair, err := KeyGeneration(*params)
+	if err != nil {
+		t.Fatalf("KeyGeneration failed: %v", err)
+	}
+	message := []byte("message")
+	signature, err := Sign(*params, keyPair.PrivateKey, message)
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+
+	// 公钥与签名都是无穷远点时配对等式对任意消息成立,必须在配对前拒绝
+	infinityG1 := NewG1Point(bn254.G1Affine{})
+	infinityG2 := NewG2Point(bn254.G2Affine{})
+	isValid, err := Verify(*params, infinityG1, BLSSignature{Message: message, Signature: infinityG2})
+	if isValid {
+		t.Fatal("Verify accepted the identity public key and signature")
+	}
+	expectError(err, "public key", ErrPointAtInfinity)
+	_, err = Verify(*params, keyPair.PublicKey, BLSSignature{Message: message, Signature: infinityG2})
+	expectError(err, "signature", ErrPointAtInfinity)
+
+	var offCurveAffine bn254.G1Affine
+	offCurveAffine.X.SetOne()
+	offCurveAffine.Y.SetOne()
+	offCurve := NewG1Point(offCurveAffine)
+	_, err = Verify(*params, offCurve, *signature)
+	expectError(err, "public key", ErrNotOnCurve)
+
+	var u bn254.E2
+	u.A0.SetUint64(7)
+	u.A1.SetUint64(11)
+	notInSubgroup := NewG2Point(bn254.MapToCurve2(&u))
+	_, err = Verify(*params, keyPair.PublicKey, BLSSignature{Message: message, Signature: notInSubgroup})
+	expectError(err, "signature", ErrNotInSubgroup)
+	_, err = DetachedVerify(*params, keyPair.PublicKey, message, notInSubgroup)
+	expectError(err, "signature", ErrNotInSubgroup)
+
+	// 聚合验证路径
+	_, err = FastAggregateVerify(*params, []G1Point{keyPair.PublicKey, infinityG1}, message, signature.Signature)
+	expectError(err, "public key", ErrPointAtInfinity)
+	_, err = AggregateVerify(*params, []G1Point{keyPair.PublicKey}, [][]byte{message}, infinityG2)
+	expectError(err, "signature", ErrPointAtInfinity)
+	_, err = PopVerify(*params, infinityG1, infinityG2)
+	expectError(err, "public key", ErrPointAtInfinity)
+
+	// 盲签名验证路径: 盲化消息与盲签名都为无穷远点时配对等式对任意公钥成立
+	isValid, err = VerifyBlindSignature(*params, keyPair.PublicKey, infinityG2, infinityG2)
+	if isValid {
+		t.Fatal("VerifyBlindSignature accepted the identity blinded message and blind signature")
+	}
+	expectError(err, "blinded message", ErrPointAtInfinity)
+	_, err = VerifyBlindSignature(*params, infinityG1, signature.Signature, signature.Signature)
+	expectError(err, "public key", ErrPointAtInfinity)
+	_, err = VerifyBlindSignature(*params, keyPair.PublicKey, signature.Signature, infinityG2)
+	expectError(err, "blind signature", ErrPointAtInfinity)
+	_, err = VerifyBlindSignature(*params, keyPair.PublicKey, notInSubgroup, signature.Signature)
+	expectError(err, "blinded message", ErrNotInSubgroup)
+	_, err = BlindSign(*params, keyPair.PrivateKey, notInSubgroup)
+	expectError(err, "blinded message", ErrNotInSubgroup)
+
+	// 批量验证将无效的项计入无效下标
+	isValid, invalid, err := BatchVerify(*params,
+		[]G1Point{keyPair.PublicKey, infinityG1, keyPair.PublicKey},
+		[]BLSSignature{*signature, {Message: message, Signature: infinityG2}, {Message: message, Signature: notInSubgroup}}, 1)
+	if err != nil || isValid || len(invalid) != 2 || invalid[0] != 1 || invalid[1] != 2 {
+		t.Fatalf("BatchVerify returned %v, %v, %v", isValid, invalid, err)
+	}
+
+	// 签名位于G1的变体
+	minSigParams, err := SetUpMinSig()
+	if err != nil {
+		t.Fatalf("SetUpMinSig failed: %v", err)
+	}
+	minSigKeyPair, err := MinSigKeyGeneration(*minSigParams)
+	if err != nil {
+		t.Fatalf("MinSigKeyGeneration failed: %v", err)
+	}
+	_, err = MinSigVerify(*minSigParams, infinityG2, MinSigSignature{Message: message, Signature: infinityG1})
+	expectError(err, "public key", ErrPointAtInfinity)
+	_, err = MinSigVerify(*minSigParams, minSigKeyPair.PublicKey, MinSigSignature{Message: message, Signature: offCurve})
+	expectError(err, "signature", ErrNotOnCurve)
+
+	if err := ValidatePublicKey(*params, keyPair.PublicKey); err != nil {
+		t.Fatalf("ValidatePublicKey rejected a valid key: %v", err)
+	}
+	if err := ValidateSignature(*params, signature.Signature); err != nil {
+		t.Fatalf("ValidateSignature rejected a valid signature: %v", err)
+	}
+
+	// 其他曲线上的点以及不属于任何曲线的零值
+	otherParams, err := SetUpOnCurve(ecc.BLS12_381)
+	if err != nil {
+		t.Fatalf("SetUpOnCurve failed: %v", err)
+	}
+	otherKeyPair, err := KeyGeneration(*otherParams)
+	if err != nil {
+		t.Fatalf("KeyGeneration failed: %v", err)
+	}
+	_, err = Verify(*params, otherKeyPair.PublicKey, *signature)
+	expectError(err, "public key", ErrCurveMismatch)
+	_, err = Verify(*params, G1Point{}, *signature)
+	expectError(err, "public key", ErrCurveMismatch)
+}
@@ -0,0 +1,314 @@
+package bls
+
+// 参考论文:
+// Craig Gentry and Alice Silverberg. "Hierarchical ID-Based Cryptography." ASIACRYPT 2002.
+// Ran Canetti, Shai Halevi and Jonathan Katz. "A Forward-Secure Public-Key Encryption Scheme." EUROCRYPT 2003.
+//
+// 论文链接: https://eprint.iacr.org/2002/056
+//
+// 前向安全签名: 时间被划分为2^depth个时段,每个时段对应深度为depth的二叉树的一个叶子,
+// 叶子的路径为时段编号的二进制表示。树中的节点密钥按Gentry-Silverberg分层签名(HIBS)派生:
+//   - 根节点: 私钥s_0,公钥pk = g1^{s_0},S_0 = 0
+//   - 派生子节点(路径ID_1..ID_t): P_t = H(ID_1..ID_t),S_t = S_{t-1} + s_{t-1} * P_t,
+//     Q_{t-1} = g1^{s_{t-1}},随机选取子节点的s_t
+//   - 叶子(时段i)签名: P_M = H(i || m),σ = S_L + s_L * P_M,同时公布Q_1..Q_L
+//   - 验证: e(g1, σ) =?= e(pk, P_1) * e(Q_1, P_2) * ... * e(Q_{L-1}, P_L) * e(Q_L, P_M)
+//
+// 私钥保存一个节点密钥栈,栈中的节点恰好覆盖当前时段及之后的所有时段,栈顶为当前时段的叶子。
+// 进入下一时段时,已经过去的节点密钥被擦除,只能向后派生而无法恢复,
+// 因此当前私钥泄露后,攻击者无法伪造过去时段的签名。
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+)
+
+// MaxForwardSecureDepth 树的最大深度,最多支持2^32个时段。
+const MaxForwardSecureDepth = 32
+
+// ForwardSecurePublicKey 前向安全签名的公钥,在所有时段中保持不变。
+type ForwardSecurePublicKey struct {
+	PublicKey G1Point
+	Depth     int
+}
+
+// ForwardSecureSignature 时段Period内对消息的签名,Qs为Q_1..Q_depth。
+type ForwardSecureSignature struct {
+	Period    uint64
+	Message   []byte
+	Signature G2Point
+	Qs        []G1Point
+}
+
+// ForwardSecurePrivateKey 当前时段的私钥,通过Update进入下一时段。
+type ForwardSecurePrivateKey struct {
+	depth  int
+	period uint64
+	// 栈顶为nodes[len(nodes)-1],即当前时段的叶子
+	nodes []*forwardSecureNode
+}
+
+// forwardSecureNode 二叉树中深度为depth、路径为prefix的节点密钥。
+type forwardSecureNode struct {
+	depth  int
+	prefix uint64
+	s      *big.Int
+	S      point
+	Qs     []point
+}
+
+// ForwardSecureKeyGeneration 生成支持2^depth个时段的前向安全密钥,私钥处于时段0。
+func ForwardSecureKeyGeneration(blsParams BLSParams, depth int) (*ForwardSecurePublicKey, *ForwardSecurePrivateKey, error) {
+	keyPair, err := KeyGeneration(blsParams)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate forward-secure key: %v", err)
+	}
+	publicKey, privateKey, err := NewForwardSecureKey(blsParams, keyPair.PrivateKey, depth)
+	eraseScalar(keyPair.PrivateKey)
+	return publicKey, privateKey, err
+}
+
+// NewForwardSecureKey 以已有的BLS私钥作为根节点私钥,公钥与BLSKeyPair.PublicKey相同。
+// 调用者应在此之后擦除原私钥,否则前向安全性不成立。
+func NewForwardSecureKey(blsParams BLSParams, privateKey *big.Int, depth int) (*ForwardSecurePublicKey, *ForwardSecurePrivateKey, error) {
+	backend, err := backendOf(blsParams, VariantMinPublicKeySize)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create forward-secure key: %v", err)
+	}
+	if depth < 1 || depth > MaxForwardSecureDepth {
+		return nil, nil, fmt.Errorf("failed to create forward-secure key: depth %d out of range [1, %d]", depth, MaxForwardSecureDepth)
+	}
+	if err := checkPrivateKey(backend, privateKey); err != nil {
+		return nil, nil, fmt.Errorf("failed to create forward-secure key: %w", err)
+	}
+	root := &forwardSecureNode{
+		s: new(big.Int).Set(privateKey),
+		S: backend.g2().infinity(),
+	}
+	key := &ForwardSecurePrivateKey{
+		depth: depth,
+		nodes: []*forwardSecureNode{root},
+	}
+	if err := key.descend(backend, blsParams, 0); err != nil {
+		return nil, nil, fmt.Errorf("failed to create forward-secure key: %v", err)
+	}
+	return &ForwardSecurePublicKey{
+		PublicKey: SkToPk(blsParams, privateKey),
+		Depth:     depth,
+	}, key, nil
+}
+
+// Period 返回私钥当前所处的时段。
+func (key *ForwardSecurePrivateKey) Period() uint64 {
+	return key.period
+}
+
+// Depth 返回树的深度,私钥共支持2^depth个时段。
+func (key *ForwardSecurePrivateKey) Depth() int {
+	return key.depth
+}
+
+// Update 进入下一时段,当前时段的叶子密钥被擦除。
+func (key *ForwardSecurePrivateKey) Update(blsParams BLSParams) error {
+	return key.UpdateTo(blsParams, key.period+1)
+}
+
+// UpdateTo 进入之后的时段period,period之前所有时段的节点密钥都被擦除。
+func (key *ForwardSecurePrivateKey) UpdateTo(blsParams BLSParams, period uint64) error {
+	if period <= key.period {
+		return fmt.Errorf("failed to update forward-secure key: period %d is not after the current period %d", period, key.period)
+	}
+	if period >= uint64(1)<<key.depth {
+		return fmt.Errorf("failed to update forward-secure key: period %d exceeds the last period %d", period, uint64(1)<<key.depth-1)
+	}
+	backend, err := backendOf(blsParams, VariantMinPublicKeySize)
+	if err != nil {
+		return fmt.Errorf("failed to update forward-secure key: %v", err)
+	}
+	if err := key.descend(backend, blsParams, period); err != nil {
+		return fmt.Errorf("failed to update forward-secure key: %v", err)
+	}
+	key.period = period
+	return nil
+}
+
+// descend 擦除覆盖范围在period之前的节点,并从覆盖period的节点向下派生到period对应的叶子。
+// 每个被拆分的节点先派生左右子节点,右子节点留在栈中供之后的时段使用,然后擦除该节点。
+func (key *ForwardSecurePrivateKey) descend(backend curveBackend, blsParams BLSParams, period uint64) error {
+	for len(key.nodes) > 0 {
+		node := key.nodes[len(key.nodes)-1]
+		if node.depth == key.depth && node.prefix == period {
+			return nil
+		}
+		key.nodes = key.nodes[:len(key.nodes)-1]
+		shift := uint(key.depth - node.depth)
+		if (node.prefix+1)<<shift <= period {
+			node.erase()
+			continue
+		}
+		left, err := node.child(backend, blsParams, 0)
+		if err != nil {
+			return err
+		}
+		right, err := node.child(backend, blsParams, 1)
+		if err != nil {
+			return err
+		}
+		node.erase()
+		key.nodes = append(key.nodes, right, left)
+	}
+	return fmt.Errorf("no key left for period %d", period)
+}
+
+// Sign 使用当前时段的叶子密钥签名: σ = S_L + s_L * H(period || m)。
+func (key *ForwardSecurePrivateKey) Sign(blsParams BLSParams, message []byte) (*ForwardSecureSignature, error) {
+	backend, err := backendOf(blsParams, VariantMinPublicKeySize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign message: %v", err)
+	}
+	if len(key.nodes) == 0 {
+		return nil, fmt.Errorf("failed to sign message: key has been erased")
+	}
+	leaf := key.nodes[len(key.nodes)-1]
+	if leaf.S.curve() != backend.curve() {
+		return nil, fmt.Errorf("failed to sign message: key is for curve %v", leaf.S.curve())
+	}
+	pm, err := forwardSecureMessagePoint(backend, key.period, message)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign message: %v", err)
+	}
+
+	qs := make([]G1Point, 0, key.depth)
+	for _, q := range leaf.Qs {
+		qs = append(qs, G1Point{q})
+	}
+	qs = append(qs, G1Point{blsParams.G1Generator.p.mul(leaf.s)})
+	return &ForwardSecureSignature{
+		Period:    key.period,
+		Message:   message,
+		Signature: G2Point{pm.mul(leaf.s).add(leaf.S)},
+		Qs:        qs,
+	}, nil
+}
+
+// Erase 擦除私钥中剩余的全部节点密钥,之后私钥不能再签名。
+func (key *ForwardSecurePrivateKey) Erase() {
+	for _, node := range key.nodes {
+		node.erase()
+	}
+	key.nodes = nil
+}
+
+// ForwardSecureVerify 验证签名:
+// e(pk, P_1) * e(Q_1, P_2) * ... * e(Q_{L-1}, P_L) * e(Q_L, P_M) * e(g1, σ)^(-1) =?= 1。
+func ForwardSecureVerify(blsParams BLSParams, publicKey ForwardSecurePublicKey, signature ForwardSecureSignature) (bool, error) {
+	backend, err := backendOf(blsParams, VariantMinPublicKeySize)
+	if err != nil {
+		return false, fmt.Errorf("failed to verify signature: %v", err)
+	}
+	depth := publicKey.Depth
+	if depth < 1 || depth > MaxForwardSecureDepth {
+		return false, fmt.Errorf("failed to verify signature: depth %d out of range [1, %d]", depth, MaxForwardSecureDepth)
+	}
+	if signature.Period >= uint64(1)<<depth {
+		return false, fmt.Errorf("failed to verify signature: period %d exceeds the last period %d", signature.Period, uint64(1)<<depth-1)
+	}
+	if err := ValidatePublicKey(blsParams, publicKey.PublicKey); err != nil {
+		return false, fmt.Errorf("failed to verify signature: %w", err)
+	}
+	if err := ValidateSignature(blsParams, signature.Signature); err != nil {
+		return false, fmt.Errorf("failed to verify signature: %w", err)
+	}
+	if len(signature.Qs) != depth {
+		return false, fmt.Errorf("failed to verify signature: expected %d Q points, got %d", depth, len(signature.Qs))
+	}
+	g1Points := make([]point, 0, depth+2)
+	g2Points := make([]point, 0, depth+2)
+	g1Points = append(g1Points, publicKey.PublicKey.p)
+	for i := range signature.Qs {
+		if err := validatePoint(backend.curve(), signature.Qs[i].p); err != nil {
+			return false, fmt.Errorf("failed to verify signature: invalid Q_%d: %v", i+1, err)
+		}
+		g1Points = append(g1Points, signature.Qs[i].p)
+	}
+	for t := 1; t <= depth; t++ {
+		p, err := forwardSecureNodePoint(backend, t, signature.Period>>uint(depth-t))
+		if err != nil {
+			return false, fmt.Errorf("failed to verify signature: %v", err)
+		}
+		g2Points = append(g2Points, p)
+	}
+	pm, err := forwardSecureMessagePoint(backend, signature.Period, signature.Message)
+	if err != nil {
+		return false, fmt.Errorf("failed to verify signature: %v", err)
+	}
+	g2Points = append(g2Points, pm)
+
+	g1Points = append(g1Points, blsParams.G1Generator.p)
+	g2Points = append(g2Points, signature.Signature.p.neg())
+	isValid, err := backend.pairingCheck(g1Points, g2Points)
+	if err != nil {
+		return false, fmt.Errorf("failed to verify signature: %v", err)
+	}
+	return isValid, nil
+}
+
+// child 派生子节点: P = H(路径),S' = S + s * P,Qs' = Qs || g1^s(根节点的g1^s即公钥,不放入Qs)。
+func (node *forwardSecureNode) child(backend curveBackend, blsParams BLSParams, bit uint64) (*forwardSecureNode, error) {
+	prefix := node.prefix<<1 | bit
+	p, err := forwardSecureNodePoint(backend, node.depth+1, prefix)
+	if err != nil {
+		return nil, err
+	}
+	s, err := randomNonZeroScalar(backend.curve().ScalarField())
+	if err != nil {
+		return nil, err
+	}
+	child := &forwardSecureNode{
+		depth:  node.depth + 1,
+		prefix: prefix,
+		s:      s,
+		S:      p.mul(node.s).add(node.S),
+		Qs:     append([]point{}, node.Qs...),
+	}
+	if node.depth > 0 {
+		child.Qs = append(child.Qs, blsParams.G1Generator.p.mul(node.s))
+	}
+	return child, nil
+}
+
+// erase 将节点的秘密值清零。
+func (node *forwardSecureNode) erase() {
+	eraseScalar(node.s)
+	node.S = nil
+}
+
+// eraseScalar 将big.Int的底层数组清零,避免秘密值残留在内存中。
+func eraseScalar(k *big.Int) {
+	words := k.Bits()
+	for i := range words {
+		words[i] = 0
+	}
+	k.SetInt64(0)
+}
+
+// forwardSecureDST 返回节点或消息的hash-to-curve DST,例如BN254上节点为"BLS_FS_BN254G2_XMD:SHA-256_SVDW_RO_NODE_"。
+func forwardSecureDST(backend curveBackend, tag string) []byte {
+	return []byte("BLS_FS_" + backend.g2().hashToCurveSuite() + tag + "_")
+}
+
+// forwardSecureNodePoint 计算深度为depth、路径为prefix的节点对应的P = H(depth || prefix)。
+func forwardSecureNodePoint(backend curveBackend, depth int, prefix uint64) (point, error) {
+	var input [9]byte
+	input[0] = byte(depth)
+	binary.BigEndian.PutUint64(input[1:], prefix)
+	return backend.g2().hashToCurve(input[:], forwardSecureDST(backend, "NODE"))
+}
+
+// forwardSecureMessagePoint 计算时段period内消息对应的P_M = H(period || m)。
+func forwardSecureMessagePoint(backend curveBackend, period uint64, message []byte) (point, error) {
+	input := make([]byte, 8, 8+len(message))
+	binary.BigEndian.PutUint64(input, period)
+	return backend.g2().hashToCurve(append(input, message...), forwardSecureDST(backend, "MESSAGE"))
+}
@@ -0,0 +1,111 @@
+package bls
+
+import (
+	"testing"
+)
+
+// TestForwardSecureSignature 测试前向安全签名在各时段的签名与验证。
+func TestForwardSecureSignature(t *testing.T) {
+	params, err := SetUp()
+	if err != nil {
+		t.Fatalf("SetUp failed: %v", err)
+	}
+	const depth = 3
+	publicKey, privateKey, err := ForwardSecureKeyGeneration(*params, depth)
+	if err != nil {
+		t.Fatalf("ForwardSecureKeyGeneration failed: %v", err)
+	}
+
+	signatures := make([]*ForwardSecureSignature, 0, 1<<depth)
+	for period := uint64(0); period < 1<<depth; period++ {
+		if privateKey.Period() != period {
+			t.Fatalf("Period() = %d, expected %d", privateKey.Period(), period)
+		}
+		// 栈中最多保存depth个右子节点与当前叶子
+		if len(privateKey.nodes) > depth+1 {
+			t.Fatalf("period %d: %d node keys kept", period, len(privateKey.nodes))
+		}
+		signature, err := privateKey.Sign(*params, []byte("attestation"))
+		if err != nil {
+			t.Fatalf("Sign failed: %v", err)
+		}
+		signatures = append(signatures, signature)
+		if period+1 < 1<<depth {
+			if err := privateKey.Update(*params); err != nil {
+				t.Fatalf("Update failed: %v", err)
+			}
+		}
+	}
+	if err := privateKey.Update(*params); err == nil {
+		t.Fatal("Update was expected to fail after the last period")
+	}
+
+	for _, signature := range signatures {
+		isValid, err := ForwardSecureVerify(*params, *publicKey, *signature)
+		if err != nil || !isValid {
+			t.Fatalf("period %d: ForwardSecureVerify failed: %v", signature.Period, err)
+		}
+		// 签名与时段绑定
+		moved := *signature
+		moved.Period = (signature.Period + 1) % (1 << depth)
+		isValid, err = ForwardSecureVerify(*params, *publicKey, moved)
+		if err != nil || isValid {
+			t.Fatalf("period %d: ForwardSecureVerify accepted a signature for another period", signature.Period)
+		}
+		tampered := *signature
+		tampered.Message = []byte("other")
+		isValid, err = ForwardSecureVerify(*params, *publicKey, tampered)
+		if err != nil || isValid {
+			t.Fatalf("period %d: ForwardSecureVerify accepted a tampered message", signature.Period)
+		}
+	}
+
+	privateKey.Erase()
+	if _, err := privateKey.Sign(*params, []byte("attestation")); err == nil {
+		t.Fatal("Sign was expected to fail after Erase")
+	}
+}
+
+// TestForwardSecureUpdateTo 测试跳过时段,以及从已有的BLS私钥创建前向安全密钥。
+func TestForwardSecureUpdateTo(t *testing.T) {
+	params, err := SetUpWithCiphersuite(CiphersuiteProofOfPossession)
+	if err != nil {
+		t.Fatalf("SetUpWithCiphersuite failed: %v", err)
+	}
+	keyPair, err := KeyGeneration(*params)
+	if err != nil {
+		t.Fatalf("KeyGeneration failed: %v", err)
+	}
+	const depth = 16
+	publicKey, privateKey, err := NewForwardSecureKey(*params, keyPair.PrivateKey, depth)
+	if err != nil {
+		t.Fatalf("NewForwardSecureKey failed: %v", err)
+	}
+	if !publicKey.PublicKey.Equal(keyPair.PublicKey) {
+		t.Fatal("forward-secure public key differs from the BLS public key")
+	}
+
+	for _, period := range []uint64{1, 1000, 1001, 40000, 1<<depth - 1} {
+		if err := privateKey.UpdateTo(*params, period); err != nil {
+			t.Fatalf("UpdateTo(%d) failed: %v", period, err)
+		}
+		signature, err := privateKey.Sign(*params, []byte("block"))
+		if err != nil {
+			t.Fatalf("Sign failed: %v", err)
+		}
+		isValid, err := ForwardSecureVerify(*params, *publicKey, *signature)
+		if err != nil || !isValid {
+			t.Fatalf("period %d: ForwardSecureVerify failed: %v", period, err)
+		}
+	}
+	if err := privateKey.UpdateTo(*params, 40000); err == nil {
+		t.Fatal("UpdateTo was expected to reject a past period")
+	}
+
+	if _, _, err := NewForwardSecureKey(*params, keyPair.PrivateKey, 0); err == nil {
+		t.Fatal("NewForwardSecureKey was expected to reject depth 0")
+	}
+	if _, _, err := NewForwardSecureKey(*params, keyPair.PrivateKey, MaxForwardSecureDepth+1); err == nil {
+		t.Fatal("NewForwardSecureKey was expected to reject a depth above the maximum")
+	}
+}
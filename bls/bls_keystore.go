@@ -0,0 +1,303 @@
+package bls
+
+// 参考规范:
+// EIP-2335: BLS12-381 Keystore. https://eips.ethereum.org/EIPS/eip-2335
+//
+// 密钥库为JSON格式,私钥经过三个模块保护:
+//   - kdf: decryption_key = PBKDF2-HMAC-SHA256(password, salt, c, dklen = 32)
+//   - checksum: SHA256(decryption_key[16:32] || cipher_message),用于检查口令是否正确
+//   - cipher: cipher_message = AES-128-CTR(decryption_key[0:16], iv, secret)
+//
+// secret为MarshalPrivateKey编码的私钥,pubkey为压缩的G1公钥(BN254上均为32字节),均以十六进制字符串表示。
+// 在EIP-2335之外增加curve字段记录私钥所在的曲线(与BDN等使用的曲线名称相同,如"BN254"、"BLS12381"),
+// 解密时拒绝与参数曲线不一致的密钥库;没有该字段的密钥库(如其他实现生成的)不做检查。
+// 口令在使用前删除C0、C1控制字符和DEL,本实现不做NFKD规范化,口令应只包含ASCII字符。
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/mmsyan/GnarkPairingProject/utils"
+	"math"
+	"strings"
+)
+
+const (
+	// KeystoreVersion 为密钥库格式的版本号。
+	KeystoreVersion = 4
+	// KeystorePBKDF2Iterations 为PBKDF2的默认迭代次数。
+	KeystorePBKDF2Iterations = 262144
+	// KeystoreMaxPBKDF2Iterations 为解密时接受的PBKDF2迭代次数上限,防止恶意密钥库消耗过多计算资源。
+	KeystoreMaxPBKDF2Iterations = 1 << 24
+
+	keystoreKeyLength = 32
+)
+
+// ErrInvalidKeystoreIterations 表示密钥库中的PBKDF2迭代次数c不在[1, KeystoreMaxPBKDF2Iterations]范围内。
+var ErrInvalidKeystoreIterations = errors.New("invalid keystore pbkdf2 iteration count")
+
+// Keystore 表示一个加密的JSON密钥库,可以直接使用encoding/json序列化。
+type Keystore struct {
+	Crypto      KeystoreCrypto `json:"crypto"`
+	Curve       string         `json:"curve,omitempty"`
+	Description string         `json:"description"`
+	PublicKey   string         `json:"pubkey"`
+	Path        string         `json:"path"`
+	UUID        string         `json:"uuid"`
+	Version     int            `json:"version"`
+}
+
+// KeystoreCrypto 包含密钥库的kdf、checksum和cipher三个模块。
+type KeystoreCrypto struct {
+	KDF      KeystoreModule `json:"kdf"`
+	Checksum KeystoreModule `json:"checksum"`
+	Cipher   KeystoreModule `json:"cipher"`
+}
+
+// KeystoreModule 表示密钥库中的一个模块,Params的内容由Function决定。
+type KeystoreModule struct {
+	Function string                 `json:"function"`
+	Params   map[string]interface{} `json:"params"`
+	Message  string                 `json:"message"`
+}
+
+// EncryptKeystore 使用口令加密密钥对,path为派生该私钥的路径(可以为空)。
+func EncryptKeystore(blsParams BLSParams, keyPair *BLSKeyPair, password string, path string) (*Keystore, error) {
+	return encryptKeystore(blsParams, keyPair, password, path, KeystorePBKDF2Iterations)
+}
+
+func encryptKeystore(blsParams BLSParams, keyPair *BLSKeyPair, password string, path string, iterations int) (*Keystore, error) {
+	backend, err := backendFor(curveOf(blsParams))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt keystore: %v", err)
+	}
+	secret, err := MarshalPrivateKey(blsParams, keyPair.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt keystore: %v", err)
+	}
+	salt := make([]byte, 32)
+	iv := make([]byte, aes.BlockSize)
+	uuid := make([]byte, 16)
+	for _, buffer := range [][]byte{salt, iv, uuid} {
+		if _, err := rand.Read(buffer); err != nil {
+			return nil, fmt.Errorf("failed to encrypt keystore: %v", err)
+		}
+	}
+
+	decryptionKey, err := utils.PBKDF2(processPassword(password), salt, iterations, keystoreKeyLength)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt keystore: %v", err)
+	}
+	cipherMessage, err := aes128CTR(decryptionKey[:16], iv, secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt keystore: %v", err)
+	}
+
+	return &Keystore{
+		Crypto: KeystoreCrypto{
+			KDF: KeystoreModule{
+				Function: "pbkdf2",
+				Params: map[string]interface{}{
+					"dklen": keystoreKeyLength,
+					"c":     iterations,
+					"prf":   "hmac-sha256",
+					"salt":  hex.EncodeToString(salt),
+				},
+			},
+			Checksum: KeystoreModule{
+				Function: "sha256",
+				Params:   map[string]interface{}{},
+				Message:  hex.EncodeToString(keystoreChecksum(decryptionKey, cipherMessage)),
+			},
+			Cipher: KeystoreModule{
+				Function: "aes-128-ctr",
+				Params: map[string]interface{}{
+					"iv": hex.EncodeToString(iv),
+				},
+				Message: hex.EncodeToString(cipherMessage),
+			},
+		},
+		Curve:     backend.name(),
+		PublicKey: hex.EncodeToString(MarshalPublicKey(keyPair.PublicKey, true)),
+		Path:      path,
+		UUID:      formatUUID(uuid),
+		Version:   KeystoreVersion,
+	}, nil
+}
+
+// Decrypt 使用口令解密密钥库,口令错误时checksum校验失败并返回错误。
+// 如果密钥库中记录了公钥,还会检查解密出的私钥与之对应。
+// PBKDF2迭代次数不在[1, KeystoreMaxPBKDF2Iterations]范围内时返回ErrInvalidKeystoreIterations,
+// 密钥库记录的曲线与参数曲线不一致时返回ErrCurveMismatch。
+func (keystore *Keystore) Decrypt(blsParams BLSParams, password string) (*BLSKeyPair, error) {
+	if keystore.Version != KeystoreVersion {
+		return nil, fmt.Errorf("failed to decrypt keystore: unsupported version %d", keystore.Version)
+	}
+	backend, err := backendFor(curveOf(blsParams))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt keystore: %v", err)
+	}
+	if keystore.Curve != "" && keystore.Curve != backend.name() {
+		return nil, fmt.Errorf("failed to decrypt keystore: %w: keystore is for %s, params are for %s", ErrCurveMismatch, keystore.Curve, backend.name())
+	}
+	decryptionKey, err := keystore.decryptionKey(password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt keystore: %w", err)
+	}
+
+	if keystore.Crypto.Checksum.Function != "sha256" {
+		return nil, fmt.Errorf("failed to decrypt keystore: unsupported checksum %q", keystore.Crypto.Checksum.Function)
+	}
+	checksum, err := hex.DecodeString(keystore.Crypto.Checksum.Message)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt keystore: invalid checksum: %v", err)
+	}
+	cipherMessage, err := hex.DecodeString(keystore.Crypto.Cipher.Message)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt keystore: invalid cipher message: %v", err)
+	}
+	if !bytes.Equal(checksum, keystoreChecksum(decryptionKey, cipherMessage)) {
+		return nil, fmt.Errorf("failed to decrypt keystore: invalid password")
+	}
+
+	if keystore.Crypto.Cipher.Function != "aes-128-ctr" {
+		return nil, fmt.Errorf("failed to decrypt keystore: unsupported cipher %q", keystore.Crypto.Cipher.Function)
+	}
+	iv, err := keystoreHexParam(keystore.Crypto.Cipher.Params, "iv")
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt keystore: %v", err)
+	}
+	secret, err := aes128CTR(decryptionKey[:16], iv, cipherMessage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt keystore: %v", err)
+	}
+	privateKey, err := UnmarshalPrivateKey(blsParams, secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt keystore: %v", err)
+	}
+
+	keyPair := &BLSKeyPair{
+		PrivateKey: privateKey,
+		PublicKey:  SkToPk(blsParams, privateKey),
+	}
+	if keystore.PublicKey != "" {
+		if keystore.PublicKey != hex.EncodeToString(MarshalPublicKey(keyPair.PublicKey, true)) {
+			return nil, fmt.Errorf("failed to decrypt keystore: public key mismatch")
+		}
+	}
+	return keyPair, nil
+}
+
+// MarshalKeystore 将密钥库序列化为JSON。
+func MarshalKeystore(keystore *Keystore) ([]byte, error) {
+	return json.MarshalIndent(keystore, "", "  ")
+}
+
+// UnmarshalKeystore 从JSON解析密钥库。
+func UnmarshalKeystore(data []byte) (*Keystore, error) {
+	var keystore Keystore
+	if err := json.Unmarshal(data, &keystore); err != nil {
+		return nil, fmt.Errorf("failed to parse keystore: %v", err)
+	}
+	return &keystore, nil
+}
+
+func (keystore *Keystore) decryptionKey(password string) ([]byte, error) {
+	kdf := keystore.Crypto.KDF
+	if kdf.Function != "pbkdf2" {
+		return nil, fmt.Errorf("unsupported kdf %q", kdf.Function)
+	}
+	if prf, _ := kdf.Params["prf"].(string); prf != "hmac-sha256" {
+		return nil, fmt.Errorf("unsupported prf %q", prf)
+	}
+	// JSON中的数字解析为float64
+	iterations, err := keystoreIntParam(kdf.Params, "c")
+	if err != nil {
+		return nil, err
+	}
+	if iterations < 1 || iterations > KeystoreMaxPBKDF2Iterations {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidKeystoreIterations, iterations)
+	}
+	keyLength, err := keystoreIntParam(kdf.Params, "dklen")
+	if err != nil {
+		return nil, err
+	}
+	if keyLength != keystoreKeyLength {
+		return nil, fmt.Errorf("unsupported dklen %d", keyLength)
+	}
+	salt, err := keystoreHexParam(kdf.Params, "salt")
+	if err != nil {
+		return nil, err
+	}
+	return utils.PBKDF2(processPassword(password), salt, iterations, keyLength)
+}
+
+func keystoreIntParam(params map[string]interface{}, name string) (int, error) {
+	switch value := params[name].(type) {
+	case int:
+		return value, nil
+	case float64:
+		// 只接受float64可以精确表示的整数(绝对值不超过2^53),避免转换时溢出
+		if value != math.Trunc(value) || math.Abs(value) > 1<<53 {
+			return 0, fmt.Errorf("invalid parameter %s", name)
+		}
+		return int(value), nil
+	default:
+		return 0, fmt.Errorf("missing parameter %s", name)
+	}
+}
+
+func keystoreHexParam(params map[string]interface{}, name string) ([]byte, error) {
+	value, ok := params[name].(string)
+	if !ok {
+		return nil, fmt.Errorf("missing parameter %s", name)
+	}
+	decoded, err := hex.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid parameter %s: %v", name, err)
+	}
+	return decoded, nil
+}
+
+func keystoreChecksum(decryptionKey []byte, cipherMessage []byte) []byte {
+	h := sha256.New()
+	h.Write(decryptionKey[16:32])
+	h.Write(cipherMessage)
+	return h.Sum(nil)
+}
+
+func aes128CTR(key []byte, iv []byte, input []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(iv) != aes.BlockSize {
+		return nil, fmt.Errorf("invalid iv length %d", len(iv))
+	}
+	output := make([]byte, len(input))
+	cipher.NewCTR(block, iv).XORKeyStream(output, input)
+	return output, nil
+}
+
+// processPassword 删除口令中的C0(0x00-0x1F)、DEL(0x7F)和C1(0x80-0x9F)控制字符。
+func processPassword(password string) []byte {
+	return []byte(strings.Map(func(r rune) rune {
+		if r < 0x20 || (r >= 0x7F && r <= 0x9F) {
+			return -1
+		}
+		return r
+	}, password))
+}
+
+// formatUUID 将16字节随机数格式化为第4版UUID。
+func formatUUID(b []byte) string {
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -0,0 +1,95 @@
+package bls
+
+import (
+	"errors"
+	"github.com/consensys/gnark-crypto/ecc"
+	"testing"
+)
+
+// TestKeystore 测试密钥库的加密、JSON序列化与解密,以及错误口令的处理。
+// 为了加快测试速度,使用较小的PBKDF2迭代次数。
+func TestKeystore(t *testing.T) {
+	params, err := SetUp()
+	if err != nil {
+		t.Fatalf("SetUp failed: %v", err)
+	}
+	keyPair, err := KeyGeneration(*params)
+	if err != nil {
+		t.Fatalf("KeyGeneration failed: %v", err)
+	}
+
+	keystore, err := encryptKeystore(*params, keyPair, "correct\x7f horse\u0085", "m/12381/3600/0/0", 1024)
+	if err != nil {
+		t.Fatalf("encryptKeystore failed: %v", err)
+	}
+	data, err := MarshalKeystore(keystore)
+	if err != nil {
+		t.Fatalf("MarshalKeystore failed: %v", err)
+	}
+	loaded, err := UnmarshalKeystore(data)
+	if err != nil {
+		t.Fatalf("UnmarshalKeystore failed: %v", err)
+	}
+	if loaded.Path != "m/12381/3600/0/0" || loaded.Version != KeystoreVersion || len(loaded.UUID) != 36 || loaded.Curve != "BN254" {
+		t.Fatalf("unexpected keystore metadata: %+v", loaded)
+	}
+
+	// 控制字符在派生密钥前被删除
+	decrypted, err := loaded.Decrypt(*params, "correct horse")
+	if err != nil {
+		t.Fatalf("Decrypt failed: %v", err)
+	}
+	if decrypted.PrivateKey.Cmp(keyPair.PrivateKey) != 0 || !decrypted.PublicKey.Equal(keyPair.PublicKey) {
+		t.Fatal("decrypted key pair does not match the original")
+	}
+
+	if _, err := loaded.Decrypt(*params, "wrong horse"); err == nil {
+		t.Fatal("Decrypt was expected to fail with a wrong password")
+	}
+	loaded.PublicKey = "00"
+	if _, err := loaded.Decrypt(*params, "correct horse"); err == nil {
+		t.Fatal("Decrypt was expected to fail with a mismatched public key")
+	}
+
+	// 记录的曲线与参数曲线不一致时拒绝解密,即使私钥的长度相同
+	bls12377Params, err := SetUpOnCurve(ecc.BLS12_377)
+	if err != nil {
+		t.Fatalf("SetUpOnCurve failed: %v", err)
+	}
+	if _, err := keystore.Decrypt(*bls12377Params, "correct horse"); !errors.Is(err, ErrCurveMismatch) {
+		t.Fatalf("Decrypt was expected to reject a keystore for another curve, got %v", err)
+	}
+	if _, err := UnmarshalKeystore([]byte("{")); err == nil {
+		t.Fatal("UnmarshalKeystore was expected to fail on invalid JSON")
+	}
+}
+
+// TestKeystoreIterations 测试解密时拒绝不在[1, 2^24]范围内的PBKDF2迭代次数。
+func TestKeystoreIterations(t *testing.T) {
+	params, err := SetUp()
+	if err != nil {
+		t.Fatalf("SetUp failed: %v", err)
+	}
+	keyPair, err := KeyGeneration(*params)
+	if err != nil {
+		t.Fatalf("KeyGeneration failed: %v", err)
+	}
+	keystore, err := encryptKeystore(*params, keyPair, "password", "", 1)
+	if err != nil {
+		t.Fatalf("encryptKeystore failed: %v", err)
+	}
+	if _, err := keystore.Decrypt(*params, "password"); err != nil {
+		t.Fatalf("Decrypt failed with c = 1: %v", err)
+	}
+
+	for _, c := range []interface{}{0, -1, float64(KeystoreMaxPBKDF2Iterations + 1), float64(1 << 40)} {
+		keystore.Crypto.KDF.Params["c"] = c
+		if _, err := keystore.Decrypt(*params, "password"); !errors.Is(err, ErrInvalidKeystoreIterations) {
+			t.Fatalf("Decrypt with c = %v returned %v, expected ErrInvalidKeystoreIterations", c, err)
+		}
+	}
+	keystore.Crypto.KDF.Params["c"] = 1e300
+	if _, err := keystore.Decrypt(*params, "password"); err == nil {
+		t.Fatal("Decrypt was expected to reject c = 1e300")
+	}
+}
@@ -0,0 +1,279 @@
+package bls
+
+import (
+	"fmt"
+	"github.com/consensys/gnark-crypto/ecc"
+	"math/big"
+)
+
+// Variant 表示公钥和签名分别位于哪个群。
+//   - VariantMinPublicKeySize: 公钥位于G1(BN254上压缩后32字节),签名位于G2(压缩后64字节),为SetUp的默认变体
+//   - VariantMinSignatureSize: 公钥位于G2(BN254上压缩后64字节),签名位于G1(压缩后32字节),签名体积减半
+type Variant int
+
+const (
+	VariantMinPublicKeySize Variant = iota
+	VariantMinSignatureSize
+)
+
+// String 返回变体的名称。
+func (variant Variant) String() string {
+	switch variant {
+	case VariantMinPublicKeySize:
+		return "minimal-pubkey-size"
+	case VariantMinSignatureSize:
+		return "minimal-signature-size"
+	default:
+		return fmt.Sprintf("Variant(%d)", int(variant))
+	}
+}
+
+// MinSigKeyPair 表示签名位于G1的变体下的密钥对,公钥为G2Generator^x。
+type MinSigKeyPair struct {
+	PrivateKey *big.Int
+	PublicKey  G2Point
+}
+
+// MinSigSignature 表示签名位于G1的变体下的签名,签名为h(m)^x,其中h将消息哈希到G1。
+type MinSigSignature struct {
+	Message   []byte
+	Signature G1Point
+}
+
+// SetUpMinSig 初始化BN254上签名位于G1、公钥位于G2的BLS参数。
+func SetUpMinSig() (*BLSParams, error) {
+	return SetUpMinSigOnCurve(ecc.BN254)
+}
+
+// SetUpMinSigOnCurve 在指定曲线上初始化签名位于G1、公钥位于G2的BLS参数。
+func SetUpMinSigOnCurve(curve ecc.ID) (*BLSParams, error) {
+	blsParams, err := SetUpOnCurve(curve)
+	if err != nil {
+		return nil, err
+	}
+	blsParams.Variant = VariantMinSignatureSize
+	return blsParams, nil
+}
+
+// SetUpMinSigWithCiphersuite 按照指定的规范方案初始化BN254上签名位于G1的BLS参数,
+// 签名DST为"BLS_SIG_BN254G1_XMD:SHA-256_SVDW_RO_{NUL,AUG,POP}_"。
+func SetUpMinSigWithCiphersuite(suite Ciphersuite) (*BLSParams, error) {
+	return SetUpMinSigOnCurveWithCiphersuite(ecc.BN254, suite)
+}
+
+// SetUpMinSigOnCurveWithCiphersuite 在指定曲线上按照规范方案初始化签名位于G1的BLS参数,
+// 签名DST为"BLS_SIG_" || G1的hash-to-curve套件 || "{NUL,AUG,POP}_"。
+func SetUpMinSigOnCurveWithCiphersuite(curve ecc.ID, suite Ciphersuite) (*BLSParams, error) {
+	return setUpWithSuite(curve, suite, VariantMinSignatureSize)
+}
+
+// MinSigKeyGeneration 生成签名位于G1的变体下的密钥对。
+func MinSigKeyGeneration(blsParams BLSParams) (*MinSigKeyPair, error) {
+	backend, err := backendOf(blsParams, VariantMinSignatureSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate key pair: %v", err)
+	}
+	x, err := generatePrivateKey(blsParams, backend.curve().ScalarField())
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate key pair: %v", err)
+	}
+
+	// private key: x <- Zq
+	// public key: g2^x
+	return &MinSigKeyPair{
+		PrivateKey: x,
+		PublicKey:  MinSigSkToPk(blsParams, x),
+	}, nil
+}
+
+// MinSigSkToPk 由私钥计算签名位于G1的变体下的公钥: pk = G2Generator^sk
+func MinSigSkToPk(blsParams BLSParams, privateKey *big.Int) G2Point {
+	if blsParams.G2Generator.p == nil {
+		return G2Point{}
+	}
+	return G2Point{blsParams.G2Generator.p.mul(privateKey)}
+}
+
+// MinSigSign 使用私钥对消息签名,签名为G1上的点h(m)^x。
+func MinSigSign(blsParams BLSParams, privateKey *big.Int, message []byte) (*MinSigSignature, error) {
+	backend, err := backendOf(blsParams, VariantMinSignatureSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign message: %v", err)
+	}
+	if err := checkPrivateKey(backend, privateKey); err != nil {
+		return nil, fmt.Errorf("failed to sign message: %w", err)
+	}
+	publicKey := MinSigSkToPk(blsParams, privateKey)
+	// compute h(m): message to point in G1
+	hm, err := backend.g1().hashToCurve(augmentMessageBytes(blsParams, publicKey.Bytes(), message), blsParams.DST)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign message: %v", err)
+	}
+
+	// compute h(m)^x
+	return &MinSigSignature{
+		Message:   message,
+		Signature: G1Point{hm.mul(privateKey)},
+	}, nil
+}
+
+// MinSigVerify 验证签名位于G1的变体下的签名。
+// 验证等式为: e(h(m), g2^x) =?= e(sigma, G2Generator)
+func MinSigVerify(blsParams BLSParams, publicKey G2Point, signature MinSigSignature) (bool, error) {
+	backend, err := backendOf(blsParams, VariantMinSignatureSize)
+	if err != nil {
+		return false, fmt.Errorf("failed to verify signature: %v", err)
+	}
+	if err := ValidateMinSigPublicKey(blsParams, publicKey); err != nil {
+		return false, fmt.Errorf("failed to verify signature: %w", err)
+	}
+	if err := ValidateMinSigSignature(blsParams, signature.Signature); err != nil {
+		return false, fmt.Errorf("failed to verify signature: %w", err)
+	}
+	hm, err := backend.g1().hashToCurve(augmentMessageBytes(blsParams, publicKey.Bytes(), signature.Message), blsParams.DST)
+	if err != nil {
+		return false, fmt.Errorf("failed to verify signature: %v", err)
+	}
+
+	// e(h(m), publicKey) * e(-sigma, G2Generator) =?= 1
+	isValid, err := backend.pairingCheck(
+		[]point{hm, signature.Signature.p.neg()},
+		[]point{publicKey.p, blsParams.G2Generator.p},
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to verify signature: %v", err)
+	}
+	return isValid, nil
+}
+
+// MinSigAggregate 将多个签名位于G1的签名聚合为一个G1上的点,所有签名必须位于同一条曲线上。
+func MinSigAggregate(signatures []MinSigSignature) (*G1Point, error) {
+	if len(signatures) == 0 {
+		return nil, fmt.Errorf("failed to aggregate signatures: empty signature set")
+	}
+	points := make([]point, len(signatures))
+	for i := range signatures {
+		points[i] = signatures[i].Signature.p
+	}
+	aggregate, err := sumPoints(points)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate signatures: %w", err)
+	}
+	return &G1Point{aggregate}, nil
+}
+
+// MinSigAggregateVerify 验证签名位于G1的变体下针对不同消息的聚合签名,规则与AggregateVerify相同。
+// 验证等式为: e(h(m_1), pk_1) * ... * e(h(m_n), pk_n) =?= e(sigma, G2Generator)
+func MinSigAggregateVerify(blsParams BLSParams, publicKeys []G2Point, messages [][]byte, aggregateSignature G1Point) (bool, error) {
+	backend, err := backendOf(blsParams, VariantMinSignatureSize)
+	if err != nil {
+		return false, fmt.Errorf("failed to verify aggregate signature: %v", err)
+	}
+	if len(publicKeys) == 0 {
+		return false, fmt.Errorf("failed to verify aggregate signature: empty public key set")
+	}
+	if len(publicKeys) != len(messages) {
+		return false, fmt.Errorf("failed to verify aggregate signature: %d public keys but %d messages", len(publicKeys), len(messages))
+	}
+	for i := range publicKeys {
+		if err := ValidateMinSigPublicKey(blsParams, publicKeys[i]); err != nil {
+			return false, fmt.Errorf("failed to verify aggregate signature: public key %d: %w", i, err)
+		}
+	}
+	if err := ValidateMinSigSignature(blsParams, aggregateSignature); err != nil {
+		return false, fmt.Errorf("failed to verify aggregate signature: %w", err)
+	}
+	if err := checkDistinctMessages(blsParams, messages); err != nil {
+		return false, fmt.Errorf("failed to verify aggregate signature: %v", err)
+	}
+
+	g1s := make([]point, 0, len(publicKeys)+1)
+	g2s := make([]point, 0, len(publicKeys)+1)
+	for i, message := range messages {
+		hm, err := backend.g1().hashToCurve(augmentMessageBytes(blsParams, publicKeys[i].Bytes(), message), blsParams.DST)
+		if err != nil {
+			return false, fmt.Errorf("failed to verify aggregate signature: %v", err)
+		}
+		g1s = append(g1s, hm)
+		g2s = append(g2s, publicKeys[i].p)
+	}
+
+	// e(h(m_1), pk_1) * ... * e(h(m_n), pk_n) * e(-sigma, G2Generator) =?= 1
+	g1s = append(g1s, aggregateSignature.p.neg())
+	g2s = append(g2s, blsParams.G2Generator.p)
+	isValid, err := backend.pairingCheck(g1s, g2s)
+	if err != nil {
+		return false, fmt.Errorf("failed to verify aggregate signature: %v", err)
+	}
+	return isValid, nil
+}
+
+// MinSigPopProve 生成签名位于G1的变体下公钥的持有性证明: pi = h_pop(pk)^x
+func MinSigPopProve(blsParams BLSParams, privateKey *big.Int) (*G1Point, error) {
+	backend, err := backendOf(blsParams, VariantMinSignatureSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prove possession: %v", err)
+	}
+	if err := checkPrivateKey(backend, privateKey); err != nil {
+		return nil, fmt.Errorf("failed to prove possession: %w", err)
+	}
+	publicKey := MinSigSkToPk(blsParams, privateKey)
+	hpk, err := backend.g1().hashToCurve(publicKey.Bytes(), blsParams.PopDST)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prove possession: %v", err)
+	}
+	return &G1Point{hpk.mul(privateKey)}, nil
+}
+
+// MinSigPopVerify 验证签名位于G1的变体下公钥的持有性证明。
+// 验证等式为: e(h_pop(pk), pk) =?= e(pi, G2Generator)
+func MinSigPopVerify(blsParams BLSParams, publicKey G2Point, proof G1Point) (bool, error) {
+	backend, err := backendOf(blsParams, VariantMinSignatureSize)
+	if err != nil {
+		return false, fmt.Errorf("failed to verify proof of possession: %v", err)
+	}
+	if err := ValidateMinSigPublicKey(blsParams, publicKey); err != nil {
+		return false, fmt.Errorf("failed to verify proof of possession: %w", err)
+	}
+	if err := validatePoint(backend.curve(), proof.p); err != nil {
+		return false, fmt.Errorf("failed to verify proof of possession: %w", &ValidationError{Object: "proof of possession", Err: err})
+	}
+	hpk, err := backend.g1().hashToCurve(publicKey.Bytes(), blsParams.PopDST)
+	if err != nil {
+		return false, fmt.Errorf("failed to verify proof of possession: %v", err)
+	}
+	isValid, err := backend.pairingCheck(
+		[]point{hpk, proof.p.neg()},
+		[]point{publicKey.p, blsParams.G2Generator.p},
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to verify proof of possession: %v", err)
+	}
+	return isValid, nil
+}
+
+// MinSigFastAggregateVerify 验证签名位于G1的变体下多个签名者对同一消息的聚合签名。
+// 调用者必须保证每个公钥都已经通过MinSigPopVerify验证。
+func MinSigFastAggregateVerify(blsParams BLSParams, publicKeys []G2Point, message []byte, aggregateSignature G1Point) (bool, error) {
+	if blsParams.Ciphersuite != CiphersuiteNone && blsParams.Ciphersuite != CiphersuiteProofOfPossession {
+		return false, fmt.Errorf("failed to verify aggregate signature: ciphersuite %v does not support fast aggregate verification", blsParams.Ciphersuite)
+	}
+	if len(publicKeys) == 0 {
+		return false, fmt.Errorf("failed to verify aggregate signature: empty public key set")
+	}
+	points := make([]point, len(publicKeys))
+	for i := range publicKeys {
+		if err := ValidateMinSigPublicKey(blsParams, publicKeys[i]); err != nil {
+			return false, fmt.Errorf("failed to verify aggregate signature: public key %d: %w", i, err)
+		}
+		points[i] = publicKeys[i].p
+	}
+	aggregatePublicKey, err := sumPoints(points)
+	if err != nil {
+		return false, fmt.Errorf("failed to verify aggregate signature: %v", err)
+	}
+	return MinSigVerify(blsParams, G2Point{aggregatePublicKey}, MinSigSignature{
+		Message:   message,
+		Signature: aggregateSignature,
+	})
+}
@@ -0,0 +1,123 @@
+package bls
+
+import (
+	"fmt"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"testing"
+)
+
+// TestMinSigFlow 测试签名位于G1的变体的密钥生成、签名、验证与聚合。
+func TestMinSigFlow(t *testing.T) {
+	params, err := SetUpMinSig()
+	if err != nil {
+		t.Fatalf("SetUpMinSig failed: %v", err)
+	}
+
+	n := 3
+	publicKeys := make([]G2Point, n)
+	messages := make([][]byte, n)
+	signatures := make([]MinSigSignature, n)
+	for i := 0; i < n; i++ {
+		keyPair, err := MinSigKeyGeneration(*params)
+		if err != nil {
+			t.Fatalf("MinSigKeyGeneration failed: %v", err)
+		}
+		messages[i] = []byte(fmt.Sprintf("message %d", i))
+		signature, err := MinSigSign(*params, keyPair.PrivateKey, messages[i])
+		if err != nil {
+			t.Fatalf("MinSigSign failed: %v", err)
+		}
+		isValid, err := MinSigVerify(*params, keyPair.PublicKey, *signature)
+		if err != nil || !isValid {
+			t.Fatalf("MinSigVerify failed: %v", err)
+		}
+		publicKeys[i] = keyPair.PublicKey
+		signatures[i] = *signature
+	}
+
+	// 篡改消息后验证应失败
+	tampered := signatures[0]
+	tampered.Message = []byte("tampered")
+	isValid, err := MinSigVerify(*params, publicKeys[0], tampered)
+	if err != nil {
+		t.Fatalf("MinSigVerify failed unexpectedly: %v", err)
+	}
+	if isValid {
+		t.Fatal("signature was expected to be invalid for a tampered message")
+	}
+
+	aggregateSignature, err := MinSigAggregate(signatures)
+	if err != nil {
+		t.Fatalf("MinSigAggregate failed: %v", err)
+	}
+	isValid, err = MinSigAggregateVerify(*params, publicKeys, messages, *aggregateSignature)
+	if err != nil || !isValid {
+		t.Fatalf("MinSigAggregateVerify failed: %v", err)
+	}
+
+	// 签名压缩后为32字节,是默认变体64字节签名的一半
+	if len(aggregateSignature.Bytes()) != bn254.SizeOfG1AffineCompressed {
+		t.Fatalf("unexpected compressed signature size %d", len(aggregateSignature.Bytes()))
+	}
+
+	// 默认变体的函数不能使用签名位于G1的参数
+	keyPair, err := MinSigKeyGeneration(*params)
+	if err != nil {
+		t.Fatalf("MinSigKeyGeneration failed: %v", err)
+	}
+	if _, err := Sign(*params, keyPair.PrivateKey, messages[0]); err == nil {
+		t.Fatal("Sign was expected to reject params of the minimal-signature-size variant")
+	}
+}
+
+// TestMinSigFastAggregateVerify 测试签名位于G1的变体下带持有性证明的同消息聚合。
+func TestMinSigFastAggregateVerify(t *testing.T) {
+	params, err := SetUpMinSigWithCiphersuite(CiphersuiteProofOfPossession)
+	if err != nil {
+		t.Fatalf("SetUpMinSigWithCiphersuite failed: %v", err)
+	}
+	id, _ := CiphersuiteProofOfPossession.MinSigID()
+	if string(params.DST) != id {
+		t.Fatalf("unexpected DST %q", params.DST)
+	}
+
+	n := 4
+	message := []byte("committee message")
+	publicKeys := make([]G2Point, n)
+	signatures := make([]MinSigSignature, n)
+	for i := 0; i < n; i++ {
+		keyPair, err := MinSigKeyGeneration(*params)
+		if err != nil {
+			t.Fatalf("MinSigKeyGeneration failed: %v", err)
+		}
+		proof, err := MinSigPopProve(*params, keyPair.PrivateKey)
+		if err != nil {
+			t.Fatalf("MinSigPopProve failed: %v", err)
+		}
+		if isValid, err := MinSigPopVerify(*params, keyPair.PublicKey, *proof); err != nil || !isValid {
+			t.Fatalf("MinSigPopVerify failed: %v", err)
+		}
+		signature, err := MinSigSign(*params, keyPair.PrivateKey, message)
+		if err != nil {
+			t.Fatalf("MinSigSign failed: %v", err)
+		}
+		publicKeys[i] = keyPair.PublicKey
+		signatures[i] = *signature
+	}
+
+	aggregateSignature, err := MinSigAggregate(signatures)
+	if err != nil {
+		t.Fatalf("MinSigAggregate failed: %v", err)
+	}
+	isValid, err := MinSigFastAggregateVerify(*params, publicKeys, message, *aggregateSignature)
+	if err != nil || !isValid {
+		t.Fatalf("MinSigFastAggregateVerify failed: %v", err)
+	}
+	isValid, err = MinSigFastAggregateVerify(*params, publicKeys[1:], message, *aggregateSignature)
+	if err != nil {
+		t.Fatalf("MinSigFastAggregateVerify failed unexpectedly: %v", err)
+	}
+	if isValid {
+		t.Fatal("aggregate signature was expected to be invalid without all signers")
+	}
+}
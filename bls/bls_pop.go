@@ -0,0 +1,99 @@
+package bls
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// PopProve 生成公钥的持有性证明(Proof of Possession)。
+// 证明为使用私钥对公钥本身的签名: pi = h_pop(pk)^x,
+// 其中h_pop使用与普通签名不同的域分隔标签BLSParams.PopDST。
+// 只有持有私钥的一方才能为公钥生成有效证明,从而抵御rogue-key攻击。
+func PopProve(blsParams BLSParams, privateKey *big.Int) (*G2Point, error) {
+	backend, err := backendOf(blsParams, VariantMinPublicKeySize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prove possession: %v", err)
+	}
+	if err := checkPrivateKey(backend, privateKey); err != nil {
+		return nil, fmt.Errorf("failed to prove possession: %w", err)
+	}
+	publicKey := SkToPk(blsParams, privateKey)
+
+	// compute h_pop(pk)
+	hpk, err := backend.g2().hashToCurve(publicKey.Bytes(), blsParams.PopDST)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prove possession: %v", err)
+	}
+	// pi = h_pop(pk)^x
+	return &G2Point{hpk.mul(privateKey)}, nil
+}
+
+// PopVerify 验证公钥的持有性证明。
+// 验证等式为: e(pk, h_pop(pk)) =?= e(G1Generator, pi)
+func PopVerify(blsParams BLSParams, publicKey G1Point, proof G2Point) (bool, error) {
+	backend, err := backendOf(blsParams, VariantMinPublicKeySize)
+	if err != nil {
+		return false, fmt.Errorf("failed to verify proof of possession: %v", err)
+	}
+	if err := ValidatePublicKey(blsParams, publicKey); err != nil {
+		return false, fmt.Errorf("failed to verify proof of possession: %w", err)
+	}
+	if err := validatePoint(backend.curve(), proof.p); err != nil {
+		return false, fmt.Errorf("failed to verify proof of possession: %w", &ValidationError{Object: "proof of possession", Err: err})
+	}
+	hpk, err := backend.g2().hashToCurve(publicKey.Bytes(), blsParams.PopDST)
+	if err != nil {
+		return false, fmt.Errorf("failed to verify proof of possession: %v", err)
+	}
+
+	// e(pk, h_pop(pk)) * e(G1Generator, -pi) =?= 1
+	isValid, err := backend.pairingCheck(
+		[]point{publicKey.p, blsParams.G1Generator.p},
+		[]point{hpk, proof.p.neg()},
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to verify proof of possession: %v", err)
+	}
+	return isValid, nil
+}
+
+// AggregatePublicKeys 将多个G1公钥聚合为一个公钥: pk = pk_1 + pk_2 + ... + pk_n,所有公钥必须位于同一条曲线上。
+// 注意: 只有在所有公钥都已通过PopVerify验证后,聚合公钥才是安全的。
+func AggregatePublicKeys(publicKeys []G1Point) (*G1Point, error) {
+	if len(publicKeys) == 0 {
+		return nil, fmt.Errorf("failed to aggregate public keys: empty public key set")
+	}
+	points := make([]point, len(publicKeys))
+	for i := range publicKeys {
+		points[i] = publicKeys[i].p
+	}
+	aggregatePublicKey, err := sumPoints(points)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate public keys: %w", err)
+	}
+	return &G1Point{aggregatePublicKey}, nil
+}
+
+// FastAggregateVerify 验证多个签名者对同一消息的聚合签名(多重签名)。
+// 公钥先被求和为一个聚合公钥,然后只需两次配对即可完成验证:
+// e(pk_1 + ... + pk_n, h(m)) =?= e(G1Generator, sigma)
+// 调用者必须保证每个公钥都已经通过PopVerify验证。
+// 规范方案中只有PROOF-OF-POSSESSION方案允许快速聚合验证。
+func FastAggregateVerify(blsParams BLSParams, publicKeys []G1Point, message []byte, aggregateSignature G2Point) (bool, error) {
+	if blsParams.Ciphersuite != CiphersuiteNone && blsParams.Ciphersuite != CiphersuiteProofOfPossession {
+		return false, fmt.Errorf("failed to verify aggregate signature: ciphersuite %v does not support fast aggregate verification", blsParams.Ciphersuite)
+	}
+	for i := range publicKeys {
+		if err := ValidatePublicKey(blsParams, publicKeys[i]); err != nil {
+			return false, fmt.Errorf("failed to verify aggregate signature: public key %d: %w", i, err)
+		}
+	}
+	aggregatePublicKey, err := AggregatePublicKeys(publicKeys)
+	if err != nil {
+		return false, fmt.Errorf("failed to verify aggregate signature: %v", err)
+	}
+	return Verify(blsParams, *aggregatePublicKey, BLSSignature{
+		Message:   message,
+		Signature: aggregateSignature,
+	})
+}
@@ -0,0 +1,109 @@
+package bls
+
+import (
+	"testing"
+)
+
+// TestPopFlow 测试持有性证明的生成与验证。
+func TestPopFlow(t *testing.T) {
+	params, err := SetUp()
+	if err != nil {
+		t.Fatalf("SetUp failed: %v", err)
+	}
+	keyPair, err := KeyGeneration(*params)
+	if err != nil {
+		t.Fatalf("KeyGeneration failed: %v", err)
+	}
+	otherKeyPair, err := KeyGeneration(*params)
+	if err != nil {
+		t.Fatalf("KeyGeneration failed: %v", err)
+	}
+
+	proof, err := PopProve(*params, keyPair.PrivateKey)
+	if err != nil {
+		t.Fatalf("PopProve failed: %v", err)
+	}
+	isValid, err := PopVerify(*params, keyPair.PublicKey, *proof)
+	if err != nil {
+		t.Fatalf("PopVerify failed unexpectedly: %v", err)
+	}
+	if !isValid {
+		t.Fatal("proof of possession was expected to be valid")
+	}
+
+	// 证明不能用于其他公钥
+	isValid, err = PopVerify(*params, otherKeyPair.PublicKey, *proof)
+	if err != nil {
+		t.Fatalf("PopVerify failed unexpectedly: %v", err)
+	}
+	if isValid {
+		t.Fatal("proof of possession was expected to be invalid for another key")
+	}
+
+	// 对公钥的普通签名(使用DST而不是PopDST)不能作为持有性证明
+	publicKeyBytes := keyPair.PublicKey.Bytes()
+	signature, err := Sign(*params, keyPair.PrivateKey, publicKeyBytes[:])
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+	isValid, err = PopVerify(*params, keyPair.PublicKey, signature.Signature)
+	if err != nil {
+		t.Fatalf("PopVerify failed unexpectedly: %v", err)
+	}
+	if isValid {
+		t.Fatal("a plain signature was expected not to verify as proof of possession")
+	}
+}
+
+// TestFastAggregateVerify 测试多个签名者对同一消息的聚合签名验证。
+func TestFastAggregateVerify(t *testing.T) {
+	params, err := SetUp()
+	if err != nil {
+		t.Fatalf("SetUp failed: %v", err)
+	}
+
+	n := 4
+	message := []byte("committee message")
+	publicKeys := make([]G1Point, n)
+	signatures := make([]BLSSignature, n)
+	for i := 0; i < n; i++ {
+		keyPair, err := KeyGeneration(*params)
+		if err != nil {
+			t.Fatalf("KeyGeneration failed: %v", err)
+		}
+		proof, err := PopProve(*params, keyPair.PrivateKey)
+		if err != nil {
+			t.Fatalf("PopProve failed: %v", err)
+		}
+		if isValid, err := PopVerify(*params, keyPair.PublicKey, *proof); err != nil || !isValid {
+			t.Fatalf("PopVerify failed: %v", err)
+		}
+		signature, err := Sign(*params, keyPair.PrivateKey, message)
+		if err != nil {
+			t.Fatalf("Sign failed: %v", err)
+		}
+		publicKeys[i] = keyPair.PublicKey
+		signatures[i] = *signature
+	}
+
+	aggregateSignature, err := Aggregate(signatures)
+	if err != nil {
+		t.Fatalf("Aggregate failed: %v", err)
+	}
+	isValid, err := FastAggregateVerify(*params, publicKeys, message, *aggregateSignature)
+	if err != nil {
+		t.Fatalf("FastAggregateVerify failed unexpectedly: %v", err)
+	}
+	if !isValid {
+		t.Fatal("aggregate signature was expected to be valid")
+	}
+
+	// 缺少一个签名者的公钥时验证应失败
+	isValid, err = FastAggregateVerify(*params, publicKeys[1:], message, *aggregateSignature)
+	if err != nil {
+		t.Fatalf("FastAggregateVerify failed unexpectedly: %v", err)
+	}
+	if isValid {
+		t.Fatal("aggregate signature was expected to be invalid without all signers")
+	}
+}
@@ -0,0 +1,123 @@
+package bls
+
+// 预计算Miller循环直线的验证器
+//
+// 配对e(P, Q)的Miller循环中,直线函数只依赖于G2上的点Q,在G1上的点P处求值。
+// 当Q在多次验证中固定不变时,可以通过PrecomputeLines预先计算全部直线,
+// 之后每次验证只需在P处求值(MillerLoopFixedQ),省去G2上的点运算。
+// 直线的类型随曲线不同,由curveBackend.precomputeLines与pairingCheckFixedQ处理。
+//
+//   - PrecomputedVerifier: 公钥位于G1的默认变体,固定消息m,预计算h(m)的直线,
+//     适用于大量签名者对同一消息签名(例如委员会对同一区块签名)
+//   - MinSigPrecomputedVerifier: 签名位于G1的变体,固定公钥pk,预计算G2Generator与pk的直线,
+//     验证的两个配对都使用预计算的直线,适用于反复验证同一签名者的签名
+
+import (
+	"fmt"
+)
+
+// PrecomputedVerifier 验证不同签名者对同一消息的签名,h(m)的直线在创建时预计算。
+type PrecomputedVerifier struct {
+	blsParams BLSParams
+	backend   curveBackend
+	message   []byte
+	lines     any
+}
+
+// NewPrecomputedVerifier 创建固定消息的验证器。
+// MESSAGE-AUGMENTATION方案下被签名的消息依赖于公钥,不支持固定消息的预计算。
+func NewPrecomputedVerifier(blsParams BLSParams, message []byte) (*PrecomputedVerifier, error) {
+	backend, err := backendOf(blsParams, VariantMinPublicKeySize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create precomputed verifier: %v", err)
+	}
+	if blsParams.Ciphersuite == CiphersuiteMessageAugmentation {
+		return nil, fmt.Errorf("failed to create precomputed verifier: ciphersuite %v signs messages that depend on the public key", blsParams.Ciphersuite)
+	}
+	hm, err := backend.g2().hashToCurve(message, blsParams.DST)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create precomputed verifier: %v", err)
+	}
+	return &PrecomputedVerifier{
+		blsParams: blsParams,
+		backend:   backend,
+		message:   append([]byte{}, message...),
+		lines:     backend.precomputeLines(hm),
+	}, nil
+}
+
+// Verify 验证publicKey对固定消息的签名: e(publicKey, h(m)) * e(G1Generator, -sigma) =?= 1,
+// 其中e(publicKey, h(m))的Miller循环使用预计算的直线。
+func (verifier *PrecomputedVerifier) Verify(publicKey G1Point, signature G2Point) (bool, error) {
+	if err := ValidatePublicKey(verifier.blsParams, publicKey); err != nil {
+		return false, fmt.Errorf("failed to verify signature: %w", err)
+	}
+	if err := ValidateSignature(verifier.blsParams, signature); err != nil {
+		return false, fmt.Errorf("failed to verify signature: %w", err)
+	}
+	isValid, err := verifier.backend.pairingCheckFixedQ(
+		[]point{publicKey.p}, []any{verifier.lines},
+		[]point{verifier.blsParams.G1Generator.p}, []point{signature.p.neg()},
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to verify signature: %v", err)
+	}
+	return isValid, nil
+}
+
+// VerifySignature 验证BLSSignature,签名中的消息必须与验证器的固定消息相同。
+func (verifier *PrecomputedVerifier) VerifySignature(publicKey G1Point, blsSignature BLSSignature) (bool, error) {
+	if string(blsSignature.Message) != string(verifier.message) {
+		return false, fmt.Errorf("failed to verify signature: message does not match the precomputed verifier")
+	}
+	return verifier.Verify(publicKey, blsSignature.Signature)
+}
+
+// MinSigPrecomputedVerifier 验证同一签名者(签名位于G1的变体)的签名,G2Generator与公钥的直线在创建时预计算。
+type MinSigPrecomputedVerifier struct {
+	blsParams BLSParams
+	backend   curveBackend
+	publicKey G2Point
+	lines     []any
+}
+
+// NewMinSigPrecomputedVerifier 创建固定公钥的验证器。
+func NewMinSigPrecomputedVerifier(blsParams BLSParams, publicKey G2Point) (*MinSigPrecomputedVerifier, error) {
+	backend, err := backendOf(blsParams, VariantMinSignatureSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create precomputed verifier: %v", err)
+	}
+	if err := ValidateMinSigPublicKey(blsParams, publicKey); err != nil {
+		return nil, fmt.Errorf("failed to create precomputed verifier: %w", err)
+	}
+	return &MinSigPrecomputedVerifier{
+		blsParams: blsParams,
+		backend:   backend,
+		publicKey: publicKey,
+		lines: []any{
+			backend.precomputeLines(publicKey.p),
+			backend.precomputeLines(blsParams.G2Generator.p),
+		},
+	}, nil
+}
+
+// PublicKey 返回验证器的固定公钥。
+func (verifier *MinSigPrecomputedVerifier) PublicKey() G2Point {
+	return verifier.publicKey
+}
+
+// Verify 验证签名: e(h(m), publicKey) * e(-sigma, G2Generator) =?= 1,两个配对都使用预计算的直线。
+func (verifier *MinSigPrecomputedVerifier) Verify(signature MinSigSignature) (bool, error) {
+	if err := ValidateMinSigSignature(verifier.blsParams, signature.Signature); err != nil {
+		return false, fmt.Errorf("failed to verify signature: %w", err)
+	}
+	hm, err := verifier.backend.g1().hashToCurve(augmentMessageBytes(verifier.blsParams, verifier.publicKey.Bytes(), signature.Message), verifier.blsParams.DST)
+	if err != nil {
+		return false, fmt.Errorf("failed to verify signature: %v", err)
+	}
+	isValid, err := verifier.backend.pairingCheckFixedQ([]point{hm, signature.Signature.p.neg()}, verifier.lines, nil, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to verify signature: %v", err)
+	}
+	return isValid, nil
+}
@@ -0,0 +1,149 @@
+package bls
+
+import (
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"testing"
+)
+
+// TestPrecomputedVerifier 测试固定消息的验证器与Verify的结果一致。
+func TestPrecomputedVerifier(t *testing.T) {
+	params, err := SetUpWithCiphersuite(CiphersuiteProofOfPossession)
+	if err != nil {
+		t.Fatalf("SetUpWithCiphersuite failed: %v", err)
+	}
+	message := []byte("block root")
+	verifier, err := NewPrecomputedVerifier(*params, message)
+	if err != nil {
+		t.Fatalf("NewPrecomputedVerifier failed: %v", err)
+	}
+	keyPairs := make([]*BLSKeyPair, 4)
+	for i := range keyPairs {
+		keyPairs[i], err = KeyGeneration(*params)
+		if err != nil {
+			t.Fatalf("KeyGeneration failed: %v", err)
+		}
+		signature, err := Sign(*params, keyPairs[i].PrivateKey, message)
+		if err != nil {
+			t.Fatalf("Sign failed: %v", err)
+		}
+		isValid, err := verifier.VerifySignature(keyPairs[i].PublicKey, *signature)
+		if err != nil || !isValid {
+			t.Fatalf("VerifySignature failed: %v", err)
+		}
+		if i > 0 {
+			isValid, err = verifier.Verify(keyPairs[i-1].PublicKey, signature.Signature)
+			if err != nil || isValid {
+				t.Fatal("Verify accepted a signature under another key")
+			}
+		}
+	}
+
+	other, err := Sign(*params, keyPairs[0].PrivateKey, []byte("other block"))
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+	if isValid, err := verifier.Verify(keyPairs[0].PublicKey, other.Signature); err != nil || isValid {
+		t.Fatal("Verify accepted a signature on another message")
+	}
+	if _, err := verifier.VerifySignature(keyPairs[0].PublicKey, *other); err == nil {
+		t.Fatal("VerifySignature was expected to reject another message")
+	}
+
+	augParams, err := SetUpWithCiphersuite(CiphersuiteMessageAugmentation)
+	if err != nil {
+		t.Fatalf("SetUpWithCiphersuite failed: %v", err)
+	}
+	if _, err := NewPrecomputedVerifier(*augParams, message); err == nil {
+		t.Fatal("NewPrecomputedVerifier was expected to reject MESSAGE-AUGMENTATION params")
+	}
+}
+
+// TestMinSigPrecomputedVerifier 测试固定公钥的验证器与MinSigVerify的结果一致。
+func TestMinSigPrecomputedVerifier(t *testing.T) {
+	for _, suite := range []Ciphersuite{CiphersuiteBasic, CiphersuiteMessageAugmentation} {
+		params, err := SetUpMinSigWithCiphersuite(suite)
+		if err != nil {
+			t.Fatalf("SetUpMinSigWithCiphersuite failed: %v", err)
+		}
+		keyPair, err := MinSigKeyGeneration(*params)
+		if err != nil {
+			t.Fatalf("MinSigKeyGeneration failed: %v", err)
+		}
+		verifier, err := NewMinSigPrecomputedVerifier(*params, keyPair.PublicKey)
+		if err != nil {
+			t.Fatalf("NewMinSigPrecomputedVerifier failed: %v", err)
+		}
+		for _, message := range [][]byte{[]byte("update 1"), []byte("update 2")} {
+			signature, err := MinSigSign(*params, keyPair.PrivateKey, message)
+			if err != nil {
+				t.Fatalf("MinSigSign failed: %v", err)
+			}
+			isValid, err := verifier.Verify(*signature)
+			if err != nil || !isValid {
+				t.Fatalf("%v: Verify failed on %s: %v", suite, message, err)
+			}
+			signature.Message = []byte("tampered")
+			isValid, err = verifier.Verify(*signature)
+			if err != nil || isValid {
+				t.Fatalf("%v: Verify accepted a tampered message", suite)
+			}
+		}
+	}
+
+	params, err := SetUp()
+	if err != nil {
+		t.Fatalf("SetUp failed: %v", err)
+	}
+	if _, err := NewMinSigPrecomputedVerifier(*params, NewG2Point(bn254.G2Affine{})); err == nil {
+		t.Fatal("NewMinSigPrecomputedVerifier was expected to reject default variant params")
+	}
+}
+
+func BenchmarkPrecomputedVerifier(b *testing.B) {
+	params, _ := SetUp()
+	message := []byte("same message")
+	keyPair, _ := KeyGeneration(*params)
+	signature, _ := Sign(*params, keyPair.PrivateKey, message)
+	verifier, err := NewPrecomputedVerifier(*params, message)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Run("Verify", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			if _, err := Verify(*params, keyPair.PublicKey, *signature); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("PrecomputedVerifier", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			if _, err := verifier.Verify(keyPair.PublicKey, signature.Signature); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkMinSigPrecomputedVerifier(b *testing.B) {
+	params, _ := SetUpMinSig()
+	keyPair, _ := MinSigKeyGeneration(*params)
+	signature, _ := MinSigSign(*params, keyPair.PrivateKey, []byte("light client update"))
+	verifier, err := NewMinSigPrecomputedVerifier(*params, keyPair.PublicKey)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Run("MinSigVerify", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			if _, err := MinSigVerify(*params, keyPair.PublicKey, *signature); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("MinSigPrecomputedVerifier", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			if _, err := verifier.Verify(*signature); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
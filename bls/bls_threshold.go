@@ -0,0 +1,140 @@
+package bls
+
+import (
+	"fmt"
+	"github.com/mmsyan/GnarkPairingProject/utils"
+	"math/big"
+)
+
+// ThresholdKeyShare 表示(t, n)门限BLS签名中第Index个服务器持有的私钥分片。
+// 私钥分片为秘密多项式在Index处的值: x_i = f(i),对应的公钥分片为G1Generator^{x_i}。
+// 下标从1开始,与utils.ComputeLagrangeBasis的约定一致。
+type ThresholdKeyShare struct {
+	Index      int
+	PrivateKey *big.Int
+	PublicKey  G1Point
+}
+
+// PartialSignature 表示第Index个服务器使用私钥分片产生的部分签名: sigma_i = h(m)^{x_i}
+type PartialSignature struct {
+	Index     int
+	Signature G2Point
+}
+
+// ThresholdKeyGeneration 由可信分发者生成门限BLS密钥: 先生成主密钥对,再将主私钥拆分为n个分片,
+// 任意t个分片可以联合签名。返回的主密钥对中的私钥应在分发后销毁。
+func ThresholdKeyGeneration(blsParams BLSParams, threshold int, n int) (*BLSKeyPair, []ThresholdKeyShare, error) {
+	keyPair, err := KeyGeneration(blsParams)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate threshold key: %v", err)
+	}
+	shares, err := SplitPrivateKey(blsParams, keyPair.PrivateKey, threshold, n)
+	if err != nil {
+		return nil, nil, err
+	}
+	return keyPair, shares, nil
+}
+
+// SplitPrivateKey 使用Shamir秘密共享将私钥拆分为n个分片。
+// 随机选择常数项为私钥、次数为t-1的多项式f,第i个分片为x_i = f(i), i = 1, ..., n。
+func SplitPrivateKey(blsParams BLSParams, privateKey *big.Int, threshold int, n int) ([]ThresholdKeyShare, error) {
+	backend, err := backendOf(blsParams, VariantMinPublicKeySize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to split private key: %v", err)
+	}
+	if threshold < 1 || threshold > n {
+		return nil, fmt.Errorf("failed to split private key: invalid threshold %d of %d", threshold, n)
+	}
+	// f(x) = x_0 + a_1*x + ... + a_{t-1}*x^{t-1}, f(0) = privateKey
+	r := backend.curve().ScalarField()
+	polynomial := utils.GenerateRandomPolynomialMod(threshold, privateKey, r)
+	shares := make([]ThresholdKeyShare, n)
+	for i := 1; i <= n; i++ {
+		xi := utils.ComputePolynomialValueMod(polynomial, big.NewInt(int64(i)), r)
+		shares[i-1] = ThresholdKeyShare{
+			Index:      i,
+			PrivateKey: xi,
+			PublicKey:  SkToPk(blsParams, xi),
+		}
+	}
+	return shares, nil
+}
+
+// PartialSign 使用私钥分片对消息产生部分签名。
+// MESSAGE-AUGMENTATION方案要求签名绑定各自的公钥,无法合并部分签名,因此不支持。
+func PartialSign(blsParams BLSParams, share ThresholdKeyShare, message []byte) (*PartialSignature, error) {
+	if blsParams.Ciphersuite == CiphersuiteMessageAugmentation {
+		return nil, fmt.Errorf("failed to sign message: threshold signing is not supported by ciphersuite %v", blsParams.Ciphersuite)
+	}
+	signature, err := Sign(blsParams, share.PrivateKey, message)
+	if err != nil {
+		return nil, err
+	}
+	return &PartialSignature{
+		Index:     share.Index,
+		Signature: signature.Signature,
+	}, nil
+}
+
+// PartialVerify 使用公钥分片验证部分签名: e(G1Generator^{x_i}, h(m)) =?= e(G1Generator, sigma_i)
+func PartialVerify(blsParams BLSParams, sharePublicKey G1Point, message []byte, partialSignature PartialSignature) (bool, error) {
+	return Verify(blsParams, sharePublicKey, BLSSignature{
+		Message:   message,
+		Signature: partialSignature.Signature,
+	})
+}
+
+// CombinePartialSignatures 在指数上进行拉格朗日插值,将任意t个部分签名合并为完整签名:
+// sigma = sum(sigma_i^{Delta_{i,S}(0)}) = h(m)^{f(0)}
+// 部分签名应先经过PartialVerify验证。多于t个时只使用前t个下标互不相同的部分签名。
+// 合并结果是普通的BLSSignature,可以直接使用主公钥通过Verify验证。
+func CombinePartialSignatures(blsParams BLSParams, threshold int, message []byte, partialSignatures []PartialSignature) (*BLSSignature, error) {
+	backend, err := backendOf(blsParams, VariantMinPublicKeySize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to combine partial signatures: %v", err)
+	}
+	if blsParams.Ciphersuite == CiphersuiteMessageAugmentation {
+		return nil, fmt.Errorf("failed to combine partial signatures: threshold signing is not supported by ciphersuite %v", blsParams.Ciphersuite)
+	}
+	if threshold < 1 {
+		return nil, fmt.Errorf("failed to combine partial signatures: invalid threshold %d", threshold)
+	}
+
+	// 选出前t个下标互不相同的部分签名
+	var s []int
+	selected := make(map[int]point, threshold)
+	for _, partialSignature := range partialSignatures {
+		if len(s) == threshold {
+			break
+		}
+		if partialSignature.Index < 1 {
+			return nil, fmt.Errorf("failed to combine partial signatures: invalid index %d", partialSignature.Index)
+		}
+		if _, ok := selected[partialSignature.Index]; ok {
+			continue
+		}
+		if err := ValidateSignature(blsParams, partialSignature.Signature); err != nil {
+			return nil, fmt.Errorf("failed to combine partial signatures: partial signature %d: %w", partialSignature.Index, err)
+		}
+		selected[partialSignature.Index] = partialSignature.Signature.p
+		s = append(s, partialSignature.Index)
+	}
+	if len(s) < threshold {
+		return nil, fmt.Errorf("failed to combine partial signatures: need %d distinct partial signatures, got %d", threshold, len(s))
+	}
+
+	combined := backend.g2().infinity()
+	for _, i := range s {
+		delta := utils.ComputeLagrangeBasisMod(i, s, 0, backend.curve().ScalarField())
+		if delta == nil {
+			return nil, fmt.Errorf("failed to combine partial signatures: failed to compute lagrange basis")
+		}
+		// sigma_i^{Delta_{i,S}(0)}
+		combined = combined.add(selected[i].mul(delta))
+	}
+
+	return &BLSSignature{
+		Message:   message,
+		Signature: G2Point{combined},
+	}, nil
+}
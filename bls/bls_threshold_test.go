@@ -0,0 +1,81 @@
+package bls
+
+import (
+	"testing"
+)
+
+// TestThresholdFlow 测试(3, 5)门限签名: 任意3个部分签名合并后可通过普通Verify验证。
+func TestThresholdFlow(t *testing.T) {
+	params, err := SetUp()
+	if err != nil {
+		t.Fatalf("SetUp failed: %v", err)
+	}
+	masterKeyPair, shares, err := ThresholdKeyGeneration(*params, 3, 5)
+	if err != nil {
+		t.Fatalf("ThresholdKeyGeneration failed: %v", err)
+	}
+
+	message := []byte("threshold message")
+	partialSignatures := make([]PartialSignature, len(shares))
+	for i, share := range shares {
+		partialSignature, err := PartialSign(*params, share, message)
+		if err != nil {
+			t.Fatalf("PartialSign failed: %v", err)
+		}
+		isValid, err := PartialVerify(*params, share.PublicKey, message, *partialSignature)
+		if err != nil || !isValid {
+			t.Fatalf("PartialVerify failed: %v", err)
+		}
+		partialSignatures[i] = *partialSignature
+	}
+
+	// 不同的3个子集都应得到相同的有效签名
+	subsets := [][]PartialSignature{
+		{partialSignatures[0], partialSignatures[1], partialSignatures[2]},
+		{partialSignatures[4], partialSignatures[1], partialSignatures[3]},
+		{partialSignatures[2], partialSignatures[2], partialSignatures[3], partialSignatures[4]},
+	}
+	for _, subset := range subsets {
+		signature, err := CombinePartialSignatures(*params, 3, message, subset)
+		if err != nil {
+			t.Fatalf("CombinePartialSignatures failed: %v", err)
+		}
+		isValid, err := Verify(*params, masterKeyPair.PublicKey, *signature)
+		if err != nil {
+			t.Fatalf("Verify failed unexpectedly: %v", err)
+		}
+		if !isValid {
+			t.Fatal("combined signature was expected to be valid")
+		}
+	}
+
+	// 不足t个部分签名时无法合并
+	_, err = CombinePartialSignatures(*params, 3, message, partialSignatures[:2])
+	if err == nil {
+		t.Fatal("CombinePartialSignatures was expected to fail with fewer than t partial signatures")
+	}
+}
+
+// TestThresholdPartialVerifyRejectsWrongShare 测试部分签名不能通过其他分片的公钥验证。
+func TestThresholdPartialVerifyRejectsWrongShare(t *testing.T) {
+	params, err := SetUp()
+	if err != nil {
+		t.Fatalf("SetUp failed: %v", err)
+	}
+	_, shares, err := ThresholdKeyGeneration(*params, 2, 3)
+	if err != nil {
+		t.Fatalf("ThresholdKeyGeneration failed: %v", err)
+	}
+	message := []byte("threshold message")
+	partialSignature, err := PartialSign(*params, shares[0], message)
+	if err != nil {
+		t.Fatalf("PartialSign failed: %v", err)
+	}
+	isValid, err := PartialVerify(*params, shares[1].PublicKey, message, *partialSignature)
+	if err != nil {
+		t.Fatalf("PartialVerify failed unexpectedly: %v", err)
+	}
+	if isValid {
+		t.Fatal("partial signature was expected to be invalid for another share")
+	}
+}
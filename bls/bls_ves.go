@@ -0,0 +1,168 @@
+package bls
+
+// 参考论文:
+// Dan Boneh, Craig Gentry, Ben Lynn and Hovav Shacham.
+// "Aggregate and Verifiably Encrypted Signatures from Bilinear Maps." EUROCRYPT 2003.
+//
+// 论文链接: https://crypto.stanford.edu/~dabo/papers/aggreg.pdf
+//
+// 可验证加密签名(VES)用于公平交换: 签名者将签名加密给仲裁者,任何人都可以验证密文中包含对消息的有效签名,
+// 但只有仲裁者能够取出签名。公钥位于G1、签名位于G2的变体下:
+//   - 仲裁者密钥: 私钥a,公钥(A1, A2) = (g1^a, g2^a)
+//   - 加密: 随机选取r,计算ω = σ * A2^r,μ = g2^r
+//   - 验证: e(g1, ω) =?= e(pk, h(m)) * e(A1, μ)
+//   - 仲裁: σ = ω / μ^a
+//
+// 论文使用同构ψ: G2 -> G1将仲裁者公钥映射到签名所在的群,支持的曲线上都不存在可高效计算的ψ,
+// 因此仲裁者同时公布A1与A2,并通过ValidateAdjudicatorKey检查e(A1, g2) = e(g1, A2)。
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// AdjudicatorPublicKey 仲裁者公钥(A1, A2) = (g1^a, g2^a)。
+type AdjudicatorPublicKey struct {
+	G1 G1Point
+	G2 G2Point
+}
+
+// AdjudicatorKeyPair 仲裁者的密钥对。
+type AdjudicatorKeyPair struct {
+	PrivateKey *big.Int
+	PublicKey  AdjudicatorPublicKey
+}
+
+// EncryptedSignature 对消息的可验证加密签名(ω, μ)。
+type EncryptedSignature struct {
+	Message []byte
+	Omega   G2Point
+	Mu      G2Point
+}
+
+// AdjudicatorKeyGeneration 生成仲裁者的密钥对。
+func AdjudicatorKeyGeneration(blsParams BLSParams) (*AdjudicatorKeyPair, error) {
+	backend, err := backendOf(blsParams, VariantMinPublicKeySize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate adjudicator key: %v", err)
+	}
+	a, err := randomNonZeroScalar(backend.curve().ScalarField())
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate adjudicator key: %v", err)
+	}
+	return &AdjudicatorKeyPair{
+		PrivateKey: a,
+		PublicKey: AdjudicatorPublicKey{
+			G1: G1Point{blsParams.G1Generator.p.mul(a)},
+			G2: G2Point{blsParams.G2Generator.p.mul(a)},
+		},
+	}, nil
+}
+
+// ValidateAdjudicatorKey 检查仲裁者公钥的两个分量都是有效的群元素,且对应同一私钥: e(A1, g2) = e(g1, A2)。
+func ValidateAdjudicatorKey(blsParams BLSParams, adjudicatorKey AdjudicatorPublicKey) error {
+	backend, err := backendOf(blsParams, VariantMinPublicKeySize)
+	if err != nil {
+		return fmt.Errorf("invalid adjudicator key: %v", err)
+	}
+	if err := validatePoint(backend.curve(), adjudicatorKey.G1.p); err != nil {
+		return fmt.Errorf("invalid adjudicator key: %v", err)
+	}
+	if err := validatePoint(backend.curve(), adjudicatorKey.G2.p); err != nil {
+		return fmt.Errorf("invalid adjudicator key: %v", err)
+	}
+	isValid, err := backend.pairingCheck(
+		[]point{adjudicatorKey.G1.p, blsParams.G1Generator.p.neg()},
+		[]point{blsParams.G2Generator.p, adjudicatorKey.G2.p},
+	)
+	if err != nil {
+		return fmt.Errorf("invalid adjudicator key: %v", err)
+	}
+	if !isValid {
+		return fmt.Errorf("invalid adjudicator key: G1 and G2 components do not match")
+	}
+	return nil
+}
+
+// VESEncrypt 将publicKey对应私钥的签名加密给仲裁者: ω = σ * A2^r,μ = g2^r。
+// 加密前会验证签名,保证生成的密文总能通过VESVerify。
+func VESEncrypt(blsParams BLSParams, publicKey G1Point, adjudicatorKey AdjudicatorPublicKey, blsSignature BLSSignature) (*EncryptedSignature, error) {
+	if err := ValidateAdjudicatorKey(blsParams, adjudicatorKey); err != nil {
+		return nil, fmt.Errorf("failed to encrypt signature: %v", err)
+	}
+	isValid, err := Verify(blsParams, publicKey, blsSignature)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt signature: %v", err)
+	}
+	if !isValid {
+		return nil, fmt.Errorf("failed to encrypt signature: invalid signature")
+	}
+	r, err := randomNonZeroScalar(curveOf(blsParams).ScalarField())
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt signature: %v", err)
+	}
+	return &EncryptedSignature{
+		Message: blsSignature.Message,
+		Omega:   G2Point{adjudicatorKey.G2.p.mul(r).add(blsSignature.Signature.p)},
+		Mu:      G2Point{blsParams.G2Generator.p.mul(r)},
+	}, nil
+}
+
+// VESSign 签名并加密给仲裁者。
+func VESSign(blsParams BLSParams, privateKey *big.Int, adjudicatorKey AdjudicatorPublicKey, message []byte) (*EncryptedSignature, error) {
+	blsSignature, err := Sign(blsParams, privateKey, message)
+	if err != nil {
+		return nil, err
+	}
+	return VESEncrypt(blsParams, SkToPk(blsParams, privateKey), adjudicatorKey, *blsSignature)
+}
+
+// VESVerify 验证密文中包含publicKey对消息的有效签名: e(pk, h(m)) * e(A1, μ) * e(g1, ω)^(-1) =?= 1。
+// 仲裁者公钥需事先通过ValidateAdjudicatorKey检查。
+func VESVerify(blsParams BLSParams, publicKey G1Point, adjudicatorKey AdjudicatorPublicKey, encryptedSignature EncryptedSignature) (bool, error) {
+	backend, err := backendOf(blsParams, VariantMinPublicKeySize)
+	if err != nil {
+		return false, fmt.Errorf("failed to verify encrypted signature: %v", err)
+	}
+	if err := ValidatePublicKey(blsParams, publicKey); err != nil {
+		return false, fmt.Errorf("failed to verify encrypted signature: %w", err)
+	}
+	if err := validatePoint(backend.curve(), adjudicatorKey.G1.p); err != nil {
+		return false, fmt.Errorf("failed to verify encrypted signature: invalid adjudicator key: %v", err)
+	}
+	if err := validatePoint(backend.curve(), encryptedSignature.Omega.p); err != nil {
+		return false, fmt.Errorf("failed to verify encrypted signature: %v", err)
+	}
+	if err := validatePoint(backend.curve(), encryptedSignature.Mu.p); err != nil {
+		return false, fmt.Errorf("failed to verify encrypted signature: %v", err)
+	}
+	hm, err := backend.g2().hashToCurve(augmentMessage(blsParams, publicKey, encryptedSignature.Message), blsParams.DST)
+	if err != nil {
+		return false, fmt.Errorf("failed to verify encrypted signature: %v", err)
+	}
+	isValid, err := backend.pairingCheck(
+		[]point{publicKey.p, adjudicatorKey.G1.p, blsParams.G1Generator.p},
+		[]point{hm, encryptedSignature.Mu.p, encryptedSignature.Omega.p.neg()},
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to verify encrypted signature: %v", err)
+	}
+	return isValid, nil
+}
+
+// Adjudicate 仲裁者取出密文中的签名: σ = ω / μ^a。
+// 仲裁前验证密文,无效的密文返回错误,取出的签名可以通过Verify验证。
+func Adjudicate(blsParams BLSParams, adjudicatorKeyPair AdjudicatorKeyPair, publicKey G1Point, encryptedSignature EncryptedSignature) (*BLSSignature, error) {
+	isValid, err := VESVerify(blsParams, publicKey, adjudicatorKeyPair.PublicKey, encryptedSignature)
+	if err != nil {
+		return nil, fmt.Errorf("failed to adjudicate: %v", err)
+	}
+	if !isValid {
+		return nil, fmt.Errorf("failed to adjudicate: invalid encrypted signature")
+	}
+	muA := encryptedSignature.Mu.p.mul(adjudicatorKeyPair.PrivateKey)
+	return &BLSSignature{
+		Message:   encryptedSignature.Message,
+		Signature: G2Point{encryptedSignature.Omega.p.sub(muA)},
+	}, nil
+}
@@ -0,0 +1,109 @@
+package bls
+
+import (
+	"testing"
+)
+
+// TestVerifiablyEncryptedSignature 测试可验证加密签名的加密、验证与仲裁。
+func TestVerifiablyEncryptedSignature(t *testing.T) {
+	for _, suite := range []Ciphersuite{CiphersuiteBasic, CiphersuiteMessageAugmentation, CiphersuiteProofOfPossession} {
+		params, err := SetUpWithCiphersuite(suite)
+		if err != nil {
+			t.Fatalf("SetUpWithCiphersuite failed: %v", err)
+		}
+		adjudicator, err := AdjudicatorKeyGeneration(*params)
+		if err != nil {
+			t.Fatalf("AdjudicatorKeyGeneration failed: %v", err)
+		}
+		if err := ValidateAdjudicatorKey(*params, adjudicator.PublicKey); err != nil {
+			t.Fatalf("ValidateAdjudicatorKey failed: %v", err)
+		}
+		signer, err := KeyGeneration(*params)
+		if err != nil {
+			t.Fatalf("KeyGeneration failed: %v", err)
+		}
+
+		message := []byte("contract")
+		encryptedSignature, err := VESSign(*params, signer.PrivateKey, adjudicator.PublicKey, message)
+		if err != nil {
+			t.Fatalf("VESSign failed: %v", err)
+		}
+		isValid, err := VESVerify(*params, signer.PublicKey, adjudicator.PublicKey, *encryptedSignature)
+		if err != nil || !isValid {
+			t.Fatalf("%v: VESVerify failed: %v", suite, err)
+		}
+		// 密文本身不是有效的签名
+		isValid, err = Verify(*params, signer.PublicKey, BLSSignature{Message: message, Signature: encryptedSignature.Omega})
+		if err != nil || isValid {
+			t.Fatalf("%v: ω was accepted as a plain signature", suite)
+		}
+
+		signature, err := Adjudicate(*params, *adjudicator, signer.PublicKey, *encryptedSignature)
+		if err != nil {
+			t.Fatalf("Adjudicate failed: %v", err)
+		}
+		isValid, err = Verify(*params, signer.PublicKey, *signature)
+		if err != nil || !isValid {
+			t.Fatalf("%v: adjudicated signature is invalid: %v", suite, err)
+		}
+
+		tampered := *encryptedSignature
+		tampered.Message = []byte("other contract")
+		isValid, err = VESVerify(*params, signer.PublicKey, adjudicator.PublicKey, tampered)
+		if err != nil || isValid {
+			t.Fatalf("%v: VESVerify accepted a tampered message", suite)
+		}
+		if _, err := Adjudicate(*params, *adjudicator, signer.PublicKey, tampered); err == nil {
+			t.Fatalf("%v: Adjudicate was expected to reject a tampered message", suite)
+		}
+	}
+}
+
+// TestVerifiablyEncryptedSignatureInvalid 测试无效的仲裁者公钥与无效的签名。
+func TestVerifiablyEncryptedSignatureInvalid(t *testing.T) {
+	params, err := SetUp()
+	if err != nil {
+		t.Fatalf("SetUp failed: %v", err)
+	}
+	adjudicator, err := AdjudicatorKeyGeneration(*params)
+	if err != nil {
+		t.Fatalf("AdjudicatorKeyGeneration failed: %v", err)
+	}
+	other, err := AdjudicatorKeyGeneration(*params)
+	if err != nil {
+		t.Fatalf("AdjudicatorKeyGeneration failed: %v", err)
+	}
+	signer, err := KeyGeneration(*params)
+	if err != nil {
+		t.Fatalf("KeyGeneration failed: %v", err)
+	}
+	signature, err := Sign(*params, signer.PrivateKey, []byte("contract"))
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+
+	// A1与A2不对应同一私钥
+	mismatched := AdjudicatorPublicKey{G1: adjudicator.PublicKey.G1, G2: other.PublicKey.G2}
+	if err := ValidateAdjudicatorKey(*params, mismatched); err == nil {
+		t.Fatal("ValidateAdjudicatorKey accepted mismatched components")
+	}
+	if _, err := VESEncrypt(*params, signer.PublicKey, mismatched, *signature); err == nil {
+		t.Fatal("VESEncrypt was expected to reject a mismatched adjudicator key")
+	}
+
+	// 加密前验证签名
+	signature.Message = []byte("other")
+	if _, err := VESEncrypt(*params, signer.PublicKey, adjudicator.PublicKey, *signature); err == nil {
+		t.Fatal("VESEncrypt was expected to reject an invalid signature")
+	}
+	signature.Message = []byte("contract")
+
+	// 其他仲裁者无法取出签名
+	encryptedSignature, err := VESEncrypt(*params, signer.PublicKey, adjudicator.PublicKey, *signature)
+	if err != nil {
+		t.Fatalf("VESEncrypt failed: %v", err)
+	}
+	if _, err := Adjudicate(*params, *other, signer.PublicKey, *encryptedSignature); err == nil {
+		t.Fatal("Adjudicate was expected to reject a ciphertext for another adjudicator")
+	}
+}
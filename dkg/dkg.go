@@ -0,0 +1,613 @@
+package dkg
+
+// 参考论文:
+// Rosario Gennaro, Stanislaw Jarecki, Hugo Krawczyk and Tal Rabin.
+// "Secure Distributed Key Generation for Discrete-Log Based Cryptosystems."
+// Journal of Cryptology 20(1), pp. 51-83. Springer, 2007.
+//
+// 论文链接: https://link.springer.com/article/10.1007/s00145-006-0347-3
+//
+// 该实现基于BN254椭圆曲线的G1群,n个参与方在没有可信分发者的情况下联合生成密钥:
+//   - 第一阶段(Pedersen-VSS): 每个参与方作为分发者,广播Pedersen承诺并秘密分发分片,
+//     收到错误分片的参与方发起投诉,被投诉的分发者公开相应分片,否则被取消资格,
+//     未被取消资格的分发者组成集合QUAL
+//   - 第二阶段(Feldman): QUAL中的分发者广播系数的Feldman承诺g^{a_ik},用于计算群公钥,
+//     承诺错误的分发者的多项式由其他参与方公开分片后重构
+//
+// 群私钥x = sum_{i in QUAL} a_i0不会被任何参与方获知,参与方j持有分片x_j = sum_{i in QUAL} f_i(j),
+// 群公钥为g^x。输出的分片可以直接用于bls包的门限签名,也可以作为BF01 IBE门限密钥生成中心的主密钥分片
+// (BF01的公共参数g1x = g^x与这里的群公钥一致)。
+
+import (
+	"crypto/rand"
+	"fmt"
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/mmsyan/GnarkPairingProject/bls"
+	"github.com/mmsyan/GnarkPairingProject/utils"
+	"math/big"
+	"sort"
+)
+
+// 协议各步骤发送消息的轮次,每一步骤接收上一轮次的消息。
+const (
+	roundDeal = iota + 1
+	roundComplaint
+	roundComplaintAnswer
+	roundFeldman
+	roundFeldmanComplaint
+	roundReveal
+)
+
+// Params 表示DKG的公共参数: 参与方数量N、门限Threshold(任意Threshold个分片可以恢复群私钥),
+// 以及G1群的两个生成元G和H,H的离散对数对任何人都是未知的。
+type Params struct {
+	N         int
+	Threshold int
+	G         bn254.G1Affine
+	H         bn254.G1Affine
+}
+
+// DealMessage 为第一阶段广播的Pedersen承诺: C_ik = g^{a_ik} * h^{b_ik}, k = 0, ..., t-1
+type DealMessage struct {
+	Commitments []bn254.G1Affine
+}
+
+// ShareMessage 为第一阶段通过点对点信道发送的分片: (s_ij, s'_ij) = (f_i(j), f'_i(j))
+type ShareMessage struct {
+	Share    *big.Int
+	Blinding *big.Int
+}
+
+// ComplaintMessage 为第一阶段针对分发者Against的投诉。
+type ComplaintMessage struct {
+	Against int
+}
+
+// ComplaintAnswerMessage 为被投诉的分发者公开的、发送给Complainer的分片。
+type ComplaintAnswerMessage struct {
+	Complainer int
+	Share      *big.Int
+	Blinding   *big.Int
+}
+
+// FeldmanMessage 为第二阶段广播的Feldman承诺: A_ik = g^{a_ik}, k = 0, ..., t-1
+type FeldmanMessage struct {
+	Commitments []bn254.G1Affine
+}
+
+// FeldmanComplaintMessage 为第二阶段针对分发者Against的投诉,附带投诉者收到的分片作为证据。
+type FeldmanComplaintMessage struct {
+	Against  int
+	Share    *big.Int
+	Blinding *big.Int
+}
+
+// RevealMessage 为参与方公开的、由Dealer发送给自己的分片,用于公开重构Dealer的多项式。
+type RevealMessage struct {
+	Dealer   int
+	Share    *big.Int
+	Blinding *big.Int
+}
+
+// KeyShare 表示DKG结束后参与方Index持有的输出。
+//   - Share: 群私钥的分片x_j
+//   - GroupPublicKey: 群公钥g^x
+//   - VerificationKeys: 所有参与方的公钥分片,VerificationKeys[i-1] = g^{x_i}
+//   - Qualified: 未被取消资格的分发者集合QUAL
+type KeyShare struct {
+	Index            int
+	Threshold        int
+	Share            *big.Int
+	GroupPublicKey   bn254.G1Affine
+	VerificationKeys []bn254.G1Affine
+	Qualified        []int
+}
+
+// Participant 表示DKG中的一个参与方,按照Deal, VerifyShares, AnswerComplaints, Qualify,
+// VerifyPublicCommitments, RevealShares, Finalize的顺序推进协议,
+// 所有参与方完成同一步骤后才能进入下一步骤。
+type Participant struct {
+	params    *Params
+	index     int
+	transport Transport
+
+	coefficients []*big.Int
+	blindings    []*big.Int
+
+	commitments    map[int][]bn254.G1Affine
+	shares         map[int]*big.Int
+	blindingShares map[int]*big.Int
+	complaints     map[int]map[int]bool
+	qualified      []int
+	feldman        map[int][]bn254.G1Affine
+	reconstruct    map[int]bool
+}
+
+// NewParams 创建DKG的公共参数,要求1 <= threshold <= n。
+func NewParams(n int, threshold int) (*Params, error) {
+	if threshold < 1 || threshold > n {
+		return nil, fmt.Errorf("invalid threshold %d of %d", threshold, n)
+	}
+	_, _, g, _ := bn254.Generators()
+	// h = hashToCurve("Pedersen Generator H") in G1, log_g(h)未知
+	h, err := bn254.HashToG1([]byte("Pedersen Generator H"), []byte("dkg Pedersen Commitment"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate dkg params: %v", err)
+	}
+	return &Params{
+		N:         n,
+		Threshold: threshold,
+		G:         g,
+		H:         h,
+	}, nil
+}
+
+// NewParticipant 创建下标为index(1 <= index <= N)的参与方。
+func NewParticipant(params *Params, index int, transport Transport) (*Participant, error) {
+	if index < 1 || index > params.N {
+		return nil, fmt.Errorf("invalid participant index %d", index)
+	}
+	return &Participant{
+		params:         params,
+		index:          index,
+		transport:      transport,
+		commitments:    make(map[int][]bn254.G1Affine),
+		shares:         make(map[int]*big.Int),
+		blindingShares: make(map[int]*big.Int),
+		complaints:     make(map[int]map[int]bool),
+		feldman:        make(map[int][]bn254.G1Affine),
+		reconstruct:    make(map[int]bool),
+	}, nil
+}
+
+// Index 返回参与方的下标。
+func (p *Participant) Index() int {
+	return p.index
+}
+
+// Deal 第一阶段第1步: 随机选择次数为t-1的多项式f_i和f'_i,广播Pedersen承诺C_ik = g^{a_ik} * h^{b_ik},
+// 并将(f_i(j), f'_i(j))秘密发送给每个参与方j。
+func (p *Participant) Deal() error {
+	q := ecc.BN254.ScalarField()
+	secret, err := rand.Int(rand.Reader, q)
+	if err != nil {
+		return fmt.Errorf("failed to deal shares: %v", err)
+	}
+	blinding, err := rand.Int(rand.Reader, q)
+	if err != nil {
+		return fmt.Errorf("failed to deal shares: %v", err)
+	}
+	p.coefficients = utils.GenerateRandomPolynomial(p.params.Threshold, secret)
+	p.blindings = utils.GenerateRandomPolynomial(p.params.Threshold, blinding)
+
+	commitments := make([]bn254.G1Affine, p.params.Threshold)
+	for k := range commitments {
+		commitments[k] = p.params.pedersen(p.coefficients[k], p.blindings[k])
+	}
+	if err := p.transport.Broadcast(p.index, roundDeal, &DealMessage{Commitments: commitments}); err != nil {
+		return fmt.Errorf("failed to deal shares: %v", err)
+	}
+
+	for j := 1; j <= p.params.N; j++ {
+		share, shareBlinding := p.evaluate(j)
+		if err := p.transport.Send(p.index, j, roundDeal, &ShareMessage{Share: share, Blinding: shareBlinding}); err != nil {
+			return fmt.Errorf("failed to deal shares: %v", err)
+		}
+	}
+	return nil
+}
+
+// VerifyShares 第一阶段第2步: 检查收到的分片是否满足g^{s_ij} * h^{s'_ij} = prod_k C_ik^{j^k},
+// 对没有承诺、没有分片或分片错误的分发者广播投诉。
+// 承诺中包含不在曲线上、不在子群中或为无穷远点的元素时视为没有广播承诺,该分发者在Qualify中被取消资格。
+func (p *Participant) VerifyShares() error {
+	messages, err := p.transport.Receive(p.index, roundDeal)
+	if err != nil {
+		return fmt.Errorf("failed to verify shares: %v", err)
+	}
+	for _, message := range messages {
+		switch payload := message.Payload.(type) {
+		case *DealMessage:
+			if message.Broadcast && p.validCommitments(payload.Commitments) {
+				p.commitments[message.From] = payload.Commitments
+			}
+		case *ShareMessage:
+			if !message.Broadcast && payload.Share != nil && payload.Blinding != nil {
+				p.shares[message.From] = payload.Share
+				p.blindingShares[message.From] = payload.Blinding
+			}
+		}
+	}
+
+	for i := 1; i <= p.params.N; i++ {
+		if p.verifyPedersenShare(i, p.index, p.shares[i], p.blindingShares[i]) {
+			continue
+		}
+		if err := p.transport.Broadcast(p.index, roundComplaint, &ComplaintMessage{Against: i}); err != nil {
+			return fmt.Errorf("failed to verify shares: %v", err)
+		}
+	}
+	return nil
+}
+
+// AnswerComplaints 第一阶段第3步: 记录所有投诉,并公开发送给投诉自己的参与方的分片。
+func (p *Participant) AnswerComplaints() error {
+	messages, err := p.transport.Receive(p.index, roundComplaint)
+	if err != nil {
+		return fmt.Errorf("failed to answer complaints: %v", err)
+	}
+	for _, message := range messages {
+		complaint, ok := message.Payload.(*ComplaintMessage)
+		if !ok || !message.Broadcast {
+			continue
+		}
+		if p.complaints[complaint.Against] == nil {
+			p.complaints[complaint.Against] = make(map[int]bool)
+		}
+		p.complaints[complaint.Against][message.From] = true
+	}
+
+	for complainer := range p.complaints[p.index] {
+		share, blinding := p.evaluate(complainer)
+		answer := &ComplaintAnswerMessage{Complainer: complainer, Share: share, Blinding: blinding}
+		if err := p.transport.Broadcast(p.index, roundComplaintAnswer, answer); err != nil {
+			return fmt.Errorf("failed to answer complaints: %v", err)
+		}
+	}
+	return nil
+}
+
+// Qualify 第一阶段第4步: 取消以下分发者的资格: 没有广播承诺、收到至少t个投诉、
+// 或者没有正确回应每一个投诉。其余分发者组成QUAL。
+// 之后QUAL中的分发者进入第二阶段,广播Feldman承诺A_ik = g^{a_ik}。
+func (p *Participant) Qualify() error {
+	messages, err := p.transport.Receive(p.index, roundComplaintAnswer)
+	if err != nil {
+		return fmt.Errorf("failed to compute qualified set: %v", err)
+	}
+	answers := make(map[int]map[int]*ComplaintAnswerMessage)
+	for _, message := range messages {
+		answer, ok := message.Payload.(*ComplaintAnswerMessage)
+		if !ok || !message.Broadcast {
+			continue
+		}
+		if answers[message.From] == nil {
+			answers[message.From] = make(map[int]*ComplaintAnswerMessage)
+		}
+		answers[message.From][answer.Complainer] = answer
+	}
+
+	p.qualified = nil
+	for i := 1; i <= p.params.N; i++ {
+		if p.commitments[i] == nil || len(p.complaints[i]) >= p.params.Threshold {
+			continue
+		}
+		answered := true
+		for complainer := range p.complaints[i] {
+			answer := answers[i][complainer]
+			if answer == nil || !p.verifyPedersenShare(i, complainer, answer.Share, answer.Blinding) {
+				answered = false
+				break
+			}
+			// 投诉者使用公开的正确分片
+			if complainer == p.index {
+				p.shares[i] = answer.Share
+				p.blindingShares[i] = answer.Blinding
+			}
+		}
+		if answered {
+			p.qualified = append(p.qualified, i)
+		}
+	}
+	if len(p.qualified) < p.params.Threshold {
+		return fmt.Errorf("failed to compute qualified set: only %d qualified dealers", len(p.qualified))
+	}
+
+	if !p.isQualified(p.index) {
+		return nil
+	}
+	commitments := make([]bn254.G1Affine, p.params.Threshold)
+	for k := range commitments {
+		commitments[k].ScalarMultiplication(&p.params.G, p.coefficients[k])
+	}
+	if err := p.transport.Broadcast(p.index, roundFeldman, &FeldmanMessage{Commitments: commitments}); err != nil {
+		return fmt.Errorf("failed to compute qualified set: %v", err)
+	}
+	return nil
+}
+
+// VerifyPublicCommitments 第二阶段第1步: 检查g^{s_ij} = prod_k A_ik^{j^k},
+// 对不满足的分发者广播投诉,并附上满足Pedersen承诺的分片(s_ij, s'_ij)作为证据。
+// 承诺中包含无效元素时视为没有广播Feldman承诺,该分发者的多项式在RevealShares中被公开重构。
+func (p *Participant) VerifyPublicCommitments() error {
+	messages, err := p.transport.Receive(p.index, roundFeldman)
+	if err != nil {
+		return fmt.Errorf("failed to verify public commitments: %v", err)
+	}
+	for _, message := range messages {
+		feldman, ok := message.Payload.(*FeldmanMessage)
+		if !ok || !message.Broadcast || !p.validCommitments(feldman.Commitments) {
+			continue
+		}
+		p.feldman[message.From] = feldman.Commitments
+	}
+
+	for _, i := range p.qualified {
+		if p.verifyFeldmanShare(i, p.index, p.shares[i]) {
+			continue
+		}
+		complaint := &FeldmanComplaintMessage{Against: i, Share: p.shares[i], Blinding: p.blindingShares[i]}
+		if err := p.transport.Broadcast(p.index, roundFeldmanComplaint, complaint); err != nil {
+			return fmt.Errorf("failed to verify public commitments: %v", err)
+		}
+	}
+	return nil
+}
+
+// RevealShares 第二阶段第2步: 对于存在有效投诉(分片满足Pedersen承诺但不满足Feldman承诺)
+// 或没有广播Feldman承诺的分发者,公开自己收到的分片,以便公开重构其多项式。
+func (p *Participant) RevealShares() error {
+	messages, err := p.transport.Receive(p.index, roundFeldmanComplaint)
+	if err != nil {
+		return fmt.Errorf("failed to reveal shares: %v", err)
+	}
+	for _, i := range p.qualified {
+		if p.feldman[i] == nil {
+			p.reconstruct[i] = true
+		}
+	}
+	for _, message := range messages {
+		complaint, ok := message.Payload.(*FeldmanComplaintMessage)
+		if !ok || !message.Broadcast || !p.isQualified(complaint.Against) {
+			continue
+		}
+		if p.verifyPedersenShare(complaint.Against, message.From, complaint.Share, complaint.Blinding) &&
+			!p.verifyFeldmanShare(complaint.Against, message.From, complaint.Share) {
+			p.reconstruct[complaint.Against] = true
+		}
+	}
+
+	for _, i := range p.sortedReconstruct() {
+		if i == p.index {
+			continue
+		}
+		reveal := &RevealMessage{Dealer: i, Share: p.shares[i], Blinding: p.blindingShares[i]}
+		if err := p.transport.Broadcast(p.index, roundReveal, reveal); err != nil {
+			return fmt.Errorf("failed to reveal shares: %v", err)
+		}
+	}
+	return nil
+}
+
+// Finalize 第二阶段第3步: 由Feldman承诺(或公开重构的分片)计算群公钥和所有参与方的公钥分片,
+// 并输出自己的私钥分片x_j = sum_{i in QUAL} s_ij。
+func (p *Participant) Finalize() (*KeyShare, error) {
+	messages, err := p.transport.Receive(p.index, roundReveal)
+	if err != nil {
+		return nil, fmt.Errorf("failed to finalize key share: %v", err)
+	}
+	reveals := make(map[int]map[int]*big.Int)
+	for _, message := range messages {
+		reveal, ok := message.Payload.(*RevealMessage)
+		if !ok || !message.Broadcast || !p.reconstruct[reveal.Dealer] || message.From == reveal.Dealer {
+			continue
+		}
+		// 只接受满足Pedersen承诺的分片
+		if !p.verifyPedersenShare(reveal.Dealer, message.From, reveal.Share, reveal.Blinding) {
+			continue
+		}
+		if reveals[reveal.Dealer] == nil {
+			reveals[reveal.Dealer] = make(map[int]*big.Int)
+		}
+		reveals[reveal.Dealer][message.From] = reveal.Share
+	}
+
+	// publicValues[x] = g^{sum_{i in QUAL} f_i(x)}, x = 0, 1, ..., n
+	publicValues := make([]bn254.G1Jac, p.params.N+1)
+	for _, i := range p.qualified {
+		var values []bn254.G1Affine
+		if p.reconstruct[i] {
+			values, err = p.reconstructedValues(reveals[i])
+			if err != nil {
+				return nil, fmt.Errorf("failed to finalize key share: dealer %d: %v", i, err)
+			}
+		} else {
+			values = make([]bn254.G1Affine, p.params.N+1)
+			for x := 0; x <= p.params.N; x++ {
+				values[x] = evaluateCommitments(p.feldman[i], x)
+			}
+		}
+		for x := range values {
+			publicValues[x].AddMixed(&values[x])
+		}
+	}
+
+	share := new(big.Int)
+	q := ecc.BN254.ScalarField()
+	for _, i := range p.qualified {
+		share.Add(share, p.shares[i])
+	}
+	share.Mod(share, q)
+
+	keyShare := &KeyShare{
+		Index:            p.index,
+		Threshold:        p.params.Threshold,
+		Share:            share,
+		VerificationKeys: make([]bn254.G1Affine, p.params.N),
+		Qualified:        append([]int{}, p.qualified...),
+	}
+	keyShare.GroupPublicKey.FromJacobian(&publicValues[0])
+	for x := 1; x <= p.params.N; x++ {
+		keyShare.VerificationKeys[x-1].FromJacobian(&publicValues[x])
+	}
+
+	// 自检: g^{x_j}必须与自己的公钥分片一致
+	var expected bn254.G1Affine
+	expected.ScalarMultiplication(&p.params.G, share)
+	if !expected.Equal(&keyShare.VerificationKeys[p.index-1]) {
+		return nil, fmt.Errorf("failed to finalize key share: share does not match verification key")
+	}
+	return keyShare, nil
+}
+
+// BLSKeyShare 将DKG输出转换为bls包的门限签名私钥分片。
+func (keyShare *KeyShare) BLSKeyShare() bls.ThresholdKeyShare {
+	return bls.ThresholdKeyShare{
+		Index:      keyShare.Index,
+		PrivateKey: new(big.Int).Set(keyShare.Share),
+		PublicKey:  bls.NewG1Point(keyShare.VerificationKeys[keyShare.Index-1]),
+	}
+}
+
+// Run 在同步网络中依次驱动所有参与方完成DKG的全部步骤,返回各参与方的输出。
+func Run(participants []*Participant) ([]*KeyShare, error) {
+	steps := []func(p *Participant) error{
+		(*Participant).Deal,
+		(*Participant).VerifyShares,
+		(*Participant).AnswerComplaints,
+		(*Participant).Qualify,
+		(*Participant).VerifyPublicCommitments,
+		(*Participant).RevealShares,
+	}
+	for _, step := range steps {
+		for _, p := range participants {
+			if err := step(p); err != nil {
+				return nil, fmt.Errorf("participant %d: %v", p.index, err)
+			}
+		}
+	}
+	keyShares := make([]*KeyShare, len(participants))
+	for i, p := range participants {
+		keyShare, err := p.Finalize()
+		if err != nil {
+			return nil, fmt.Errorf("participant %d: %v", p.index, err)
+		}
+		keyShares[i] = keyShare
+	}
+	return keyShares, nil
+}
+
+// evaluate 计算(f_i(x), f'_i(x))。
+func (p *Participant) evaluate(x int) (*big.Int, *big.Int) {
+	xElement := big.NewInt(int64(x))
+	return utils.ComputePolynomialValue(p.coefficients, xElement), utils.ComputePolynomialValue(p.blindings, xElement)
+}
+
+// verifyPedersenShare 检查分发者dealer发送给参与方j的分片: g^s * h^{s'} =?= prod_k C_k^{j^k}
+func (p *Participant) verifyPedersenShare(dealer int, j int, share *big.Int, blinding *big.Int) bool {
+	commitments := p.commitments[dealer]
+	if commitments == nil || share == nil || blinding == nil {
+		return false
+	}
+	expected := evaluateCommitments(commitments, j)
+	actual := p.params.pedersen(share, blinding)
+	return actual.Equal(&expected)
+}
+
+// verifyFeldmanShare 检查分发者dealer发送给参与方j的分片: g^s =?= prod_k A_k^{j^k}
+func (p *Participant) verifyFeldmanShare(dealer int, j int, share *big.Int) bool {
+	commitments := p.feldman[dealer]
+	if commitments == nil || share == nil {
+		return false
+	}
+	expected := evaluateCommitments(commitments, j)
+	var actual bn254.G1Affine
+	actual.ScalarMultiplication(&p.params.G, share)
+	return actual.Equal(&expected)
+}
+
+// reconstructedValues 由至少t个公开的分片在指数上插值,计算g^{f_i(x)}, x = 0, 1, ..., n。
+func (p *Participant) reconstructedValues(reveals map[int]*big.Int) ([]bn254.G1Affine, error) {
+	if len(reveals) < p.params.Threshold {
+		return nil, fmt.Errorf("need %d revealed shares, got %d", p.params.Threshold, len(reveals))
+	}
+	var s []int
+	for k := range reveals {
+		s = append(s, k)
+	}
+	sort.Ints(s)
+	s = s[:p.params.Threshold]
+
+	points := make(map[int]bn254.G1Affine, len(s))
+	for _, k := range s {
+		var point bn254.G1Affine
+		point.ScalarMultiplication(&p.params.G, reveals[k])
+		points[k] = point
+	}
+	values := make([]bn254.G1Affine, p.params.N+1)
+	for x := 0; x <= p.params.N; x++ {
+		var value bn254.G1Jac
+		for _, k := range s {
+			delta := utils.ComputeLagrangeBasis(k, s, x)
+			if delta == nil {
+				return nil, fmt.Errorf("failed to compute lagrange basis")
+			}
+			point := points[k]
+			var term bn254.G1Jac
+			term.FromAffine(&point)
+			term.ScalarMultiplication(&term, delta)
+			value.AddAssign(&term)
+		}
+		values[x].FromJacobian(&value)
+	}
+	return values, nil
+}
+
+// validCommitments 检查承诺恰好包含t个元素,且每个元素都在G1上、位于素数阶子群中且不是无穷远点。
+func (p *Participant) validCommitments(commitments []bn254.G1Affine) bool {
+	if len(commitments) != p.params.Threshold {
+		return false
+	}
+	for k := range commitments {
+		if commitments[k].IsInfinity() || !commitments[k].IsOnCurve() || !commitments[k].IsInSubGroup() {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *Participant) isQualified(i int) bool {
+	for _, j := range p.qualified {
+		if i == j {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Participant) sortedReconstruct() []int {
+	var dealers []int
+	for i := range p.reconstruct {
+		dealers = append(dealers, i)
+	}
+	sort.Ints(dealers)
+	return dealers
+}
+
+// pedersen 计算Pedersen承诺g^a * h^b。
+func (params *Params) pedersen(a *big.Int, b *big.Int) bn254.G1Affine {
+	var ga, hb bn254.G1Jac
+	ga.FromAffine(&params.G)
+	ga.ScalarMultiplication(&ga, a)
+	hb.FromAffine(&params.H)
+	hb.ScalarMultiplication(&hb, b)
+	ga.AddAssign(&hb)
+	var result bn254.G1Affine
+	result.FromJacobian(&ga)
+	return result
+}
+
+// evaluateCommitments 使用秦九韶算法在指数上计算prod_k C_k^{x^k}。
+func evaluateCommitments(commitments []bn254.G1Affine, x int) bn254.G1Affine {
+	xElement := big.NewInt(int64(x))
+	var result bn254.G1Jac
+	result.FromAffine(&commitments[len(commitments)-1])
+	for k := len(commitments) - 2; k >= 0; k-- {
+		result.ScalarMultiplication(&result, xElement)
+		result.AddMixed(&commitments[k])
+	}
+	var affine bn254.G1Affine
+	affine.FromJacobian(&result)
+	return affine
+}
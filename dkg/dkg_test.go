@@ -0,0 +1,154 @@
+package dkg
+
+import (
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/mmsyan/GnarkPairingProject/bls"
+	"github.com/mmsyan/GnarkPairingProject/utils"
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+func newParticipants(t *testing.T, n int, threshold int, transport Transport) []*Participant {
+	params, err := NewParams(n, threshold)
+	if err != nil {
+		t.Fatalf("NewParams failed: %v", err)
+	}
+	participants := make([]*Participant, n)
+	for i := 1; i <= n; i++ {
+		participants[i-1], err = NewParticipant(params, i, transport)
+		if err != nil {
+			t.Fatalf("NewParticipant failed: %v", err)
+		}
+	}
+	return participants
+}
+
+// checkKeyShares 检查所有参与方的输出一致,且任意t个分片插值得到的私钥与群公钥对应。
+func checkKeyShares(t *testing.T, keyShares []*KeyShare, threshold int) {
+	for _, keyShare := range keyShares[1:] {
+		if !keyShare.GroupPublicKey.Equal(&keyShares[0].GroupPublicKey) {
+			t.Fatal("participants disagree on the group public key")
+		}
+		if !reflect.DeepEqual(keyShare.VerificationKeys, keyShares[0].VerificationKeys) {
+			t.Fatal("participants disagree on the verification keys")
+		}
+		if !reflect.DeepEqual(keyShare.Qualified, keyShares[0].Qualified) {
+			t.Fatal("participants disagree on the qualified set")
+		}
+	}
+
+	s := make([]int, threshold)
+	for k := range s {
+		s[k] = keyShares[len(keyShares)-1-k].Index
+	}
+	secret := new(big.Int)
+	for _, i := range s {
+		term := new(big.Int).Mul(utils.ComputeLagrangeBasis(i, s, 0), keyShares[i-1].Share)
+		secret.Add(secret, term)
+	}
+	secret.Mod(secret, ecc.BN254.ScalarField())
+	groupPublicKey := *new(bn254.G1Affine).ScalarMultiplicationBase(secret)
+	if !groupPublicKey.Equal(&keyShares[0].GroupPublicKey) {
+		t.Fatal("interpolated secret does not match the group public key")
+	}
+}
+
+// TestDKGHonest 测试所有参与方诚实时的DKG,以及输出分片用于门限BLS签名。
+func TestDKGHonest(t *testing.T) {
+	participants := newParticipants(t, 5, 3, NewInMemoryTransport(5))
+	keyShares, err := Run(participants)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	checkKeyShares(t, keyShares, 3)
+	if !reflect.DeepEqual(keyShares[0].Qualified, []int{1, 2, 3, 4, 5}) {
+		t.Fatalf("unexpected qualified set: %v", keyShares[0].Qualified)
+	}
+
+	params, err := bls.SetUp()
+	if err != nil {
+		t.Fatalf("SetUp failed: %v", err)
+	}
+	message := []byte("signed by a dkg key")
+	var partialSignatures []bls.PartialSignature
+	for _, keyShare := range keyShares[1:4] {
+		partialSignature, err := bls.PartialSign(*params, keyShare.BLSKeyShare(), message)
+		if err != nil {
+			t.Fatalf("PartialSign failed: %v", err)
+		}
+		isValid, err := bls.PartialVerify(*params, bls.NewG1Point(keyShare.VerificationKeys[keyShare.Index-1]), message, *partialSignature)
+		if err != nil || !isValid {
+			t.Fatalf("PartialVerify failed: %v", err)
+		}
+		partialSignatures = append(partialSignatures, *partialSignature)
+	}
+	signature, err := bls.CombinePartialSignatures(*params, 3, message, partialSignatures)
+	if err != nil {
+		t.Fatalf("CombinePartialSignatures failed: %v", err)
+	}
+	isValid, err := bls.Verify(*params, bls.NewG1Point(keyShares[0].GroupPublicKey), *signature)
+	if err != nil || !isValid {
+		t.Fatalf("Verify failed: %v", err)
+	}
+}
+
+// TestDKGComplaints 测试恶意分发者:
+//   - 参与方2向参与方5发送错误分片,回应投诉后仍然合格
+//   - 参与方3向3个参与方发送错误分片,收到至少t个投诉被取消资格
+//   - 参与方4广播错误的Feldman承诺,其多项式被公开重构
+func TestDKGComplaints(t *testing.T) {
+	transport := newTamperingTransport(6)
+	transport.corruptShare(2, 5)
+	transport.corruptShare(3, 1)
+	transport.corruptShare(3, 2)
+	transport.corruptShare(3, 6)
+	transport.feldman[4] = func(commitments []bn254.G1Affine) {
+		_, _, g, _ := bn254.Generators()
+		commitments[0].Add(&commitments[0], &g)
+	}
+	participants := newParticipants(t, 6, 3, transport)
+
+	keyShares, err := Run(participants)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if !reflect.DeepEqual(keyShares[0].Qualified, []int{1, 2, 4, 5, 6}) {
+		t.Fatalf("unexpected qualified set: %v", keyShares[0].Qualified)
+	}
+	checkKeyShares(t, keyShares, 3)
+}
+
+// TestDKGInvalidCommitments 测试承诺中包含无效元素的分发者:
+//   - 参与方2的Pedersen承诺包含不在曲线上的点,参与方3的Pedersen承诺包含无穷远点,均被取消资格
+//   - 参与方4的Feldman承诺包含不在曲线上的点,参与方5的Feldman承诺包含无穷远点,其多项式被公开重构
+func TestDKGInvalidCommitments(t *testing.T) {
+	offCurve := func(commitments []bn254.G1Affine) {
+		commitments[1].X.SetOne()
+		commitments[1].Y.SetOne()
+	}
+	infinity := func(commitments []bn254.G1Affine) {
+		commitments[0] = bn254.G1Affine{}
+	}
+	transport := newTamperingTransport(6)
+	transport.deal[2] = offCurve
+	transport.deal[3] = infinity
+	transport.feldman[4] = offCurve
+	transport.feldman[5] = infinity
+	participants := newParticipants(t, 6, 3, transport)
+
+	keyShares, err := Run(participants)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if !reflect.DeepEqual(keyShares[0].Qualified, []int{1, 4, 5, 6}) {
+		t.Fatalf("unexpected qualified set: %v", keyShares[0].Qualified)
+	}
+	checkKeyShares(t, keyShares, 3)
+	for _, participant := range participants {
+		if !participant.reconstruct[4] || !participant.reconstruct[5] || participant.reconstruct[6] {
+			t.Fatalf("participant %d reconstructs %v", participant.index, participant.reconstruct)
+		}
+	}
+}
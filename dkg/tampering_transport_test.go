@@ -0,0 +1,59 @@
+package dkg
+
+import (
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"math/big"
+)
+
+// tamperingTransport 包装一个Transport,在发送前篡改指定分发者的消息,用于在测试中模拟恶意分发者。
+//   - shares[i][j]: 分发者i发送给参与方j的分片加1
+//   - deal[i]: 篡改分发者i广播的Pedersen承诺
+//   - feldman[i]: 篡改分发者i广播的Feldman承诺
+type tamperingTransport struct {
+	Transport
+	shares  map[int]map[int]bool
+	deal    map[int]func(commitments []bn254.G1Affine)
+	feldman map[int]func(commitments []bn254.G1Affine)
+}
+
+func newTamperingTransport(n int) *tamperingTransport {
+	return &tamperingTransport{
+		Transport: NewInMemoryTransport(n),
+		shares:    make(map[int]map[int]bool),
+		deal:      make(map[int]func(commitments []bn254.G1Affine)),
+		feldman:   make(map[int]func(commitments []bn254.G1Affine)),
+	}
+}
+
+// corruptShare 使分发者dealer发送给参与方to的分片错误。
+func (transport *tamperingTransport) corruptShare(dealer int, to int) {
+	if transport.shares[dealer] == nil {
+		transport.shares[dealer] = make(map[int]bool)
+	}
+	transport.shares[dealer][to] = true
+}
+
+func (transport *tamperingTransport) Broadcast(from int, round int, payload interface{}) error {
+	switch message := payload.(type) {
+	case *DealMessage:
+		if tamper := transport.deal[from]; tamper != nil {
+			commitments := append([]bn254.G1Affine{}, message.Commitments...)
+			tamper(commitments)
+			payload = &DealMessage{Commitments: commitments}
+		}
+	case *FeldmanMessage:
+		if tamper := transport.feldman[from]; tamper != nil {
+			commitments := append([]bn254.G1Affine{}, message.Commitments...)
+			tamper(commitments)
+			payload = &FeldmanMessage{Commitments: commitments}
+		}
+	}
+	return transport.Transport.Broadcast(from, round, payload)
+}
+
+func (transport *tamperingTransport) Send(from int, to int, round int, payload interface{}) error {
+	if message, ok := payload.(*ShareMessage); ok && transport.shares[from][to] {
+		payload = &ShareMessage{Share: new(big.Int).Add(message.Share, big.NewInt(1)), Blinding: message.Blinding}
+	}
+	return transport.Transport.Send(from, to, round, payload)
+}
@@ -0,0 +1,110 @@
+package dkg
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Message 表示DKG参与方之间传递的一条消息,Round为发送消息的轮次。
+// Broadcast为true时消息通过广播信道发送给所有参与方(包括发送者自己),否则为发送给To的点对点消息。
+// Payload为本包中定义的各轮消息类型之一。
+type Message struct {
+	Round     int
+	From      int
+	To        int
+	Broadcast bool
+	Payload   interface{}
+}
+
+// Transport 表示DKG所需的通信层。
+// 协议假设同步网络: 参与方按轮次推进,在第r+1轮开始时通过Receive取出第r轮发送给自己的全部消息。
+// 广播信道要求所有参与方收到相同的广播消息,点对点信道要求保密且经过认证。
+type Transport interface {
+	// Broadcast 在第round轮将消息广播给所有参与方。
+	Broadcast(from int, round int, payload interface{}) error
+	// Send 在第round轮将消息通过点对点信道发送给参与方to。
+	Send(from int, to int, round int, payload interface{}) error
+	// Receive 取出第round轮发送给参与方to的全部消息。
+	Receive(to int, round int) ([]Message, error)
+}
+
+// InMemoryTransport 是基于内存队列的Transport实现,用于测试和单进程模拟。
+// 可以被多个goroutine并发使用。
+type InMemoryTransport struct {
+	mutex sync.Mutex
+	n     int
+	inbox map[inboxKey][]Message
+}
+
+type inboxKey struct {
+	to    int
+	round int
+}
+
+// NewInMemoryTransport 创建一个连接参与方1, 2, ..., n的内存通信层。
+func NewInMemoryTransport(n int) *InMemoryTransport {
+	return &InMemoryTransport{
+		n:     n,
+		inbox: make(map[inboxKey][]Message),
+	}
+}
+
+// Broadcast 将消息放入所有参与方(包括发送者自己)的队列。
+func (transport *InMemoryTransport) Broadcast(from int, round int, payload interface{}) error {
+	if err := transport.checkIndex(from); err != nil {
+		return err
+	}
+	transport.mutex.Lock()
+	defer transport.mutex.Unlock()
+	for to := 1; to <= transport.n; to++ {
+		key := inboxKey{to: to, round: round}
+		transport.inbox[key] = append(transport.inbox[key], Message{
+			Round:     round,
+			From:      from,
+			To:        to,
+			Broadcast: true,
+			Payload:   payload,
+		})
+	}
+	return nil
+}
+
+// Send 将消息放入参与方to的队列。
+func (transport *InMemoryTransport) Send(from int, to int, round int, payload interface{}) error {
+	if err := transport.checkIndex(from); err != nil {
+		return err
+	}
+	if err := transport.checkIndex(to); err != nil {
+		return err
+	}
+	transport.mutex.Lock()
+	defer transport.mutex.Unlock()
+	key := inboxKey{to: to, round: round}
+	transport.inbox[key] = append(transport.inbox[key], Message{
+		Round:   round,
+		From:    from,
+		To:      to,
+		Payload: payload,
+	})
+	return nil
+}
+
+// Receive 取出并清空参与方to在第round轮的队列。
+func (transport *InMemoryTransport) Receive(to int, round int) ([]Message, error) {
+	if err := transport.checkIndex(to); err != nil {
+		return nil, err
+	}
+	transport.mutex.Lock()
+	defer transport.mutex.Unlock()
+	key := inboxKey{to: to, round: round}
+	messages := transport.inbox[key]
+	delete(transport.inbox, key)
+	return messages, nil
+}
+
+func (transport *InMemoryTransport) checkIndex(index int) error {
+	if index < 1 || index > transport.n {
+		return fmt.Errorf("invalid participant index %d", index)
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package ibe
+
+// Boneh-Franklin FullIdent: 通过Fujisaki-Okamoto变换得到的IND-ID-CCA安全方案
+//
+// BasicIdent(Encrypt/Decrypt)的密文可以被篡改: 翻转C2的比特会翻转明文的对应比特。
+// FullIdent(论文第4.2节)对随机值σ加密,并由σ和消息重新导出随机数r:
+//   - 加密: 随机选取σ,r = H3(σ, M),U = g^r,V = σ ⊕ H2(e(g^x, Qid)^r),W = M ⊕ H4(σ)
+//   - 解密: σ = V ⊕ H2(e(U, sk)),M = W ⊕ H4(σ),r = H3(σ, M),检查U = g^r,否则拒绝密文
+//
+// H2、H4使用expand_message_xmd(SHA-256),H3使用hash-to-field(fr.Hash),各自使用不同的域分隔标签。
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/field/hash"
+	"github.com/mmsyan/GnarkPairingProject/utils"
+	"math/big"
+)
+
+const (
+	// BFIBESigmaSize FullIdent中随机值σ的字节数。
+	BFIBESigmaSize = 32
+
+	bfFullIdentH2DST = "BF01_FULLIDENT_BN254_XMD:SHA-256_H2_"
+	bfFullIdentH3DST = "BF01_FULLIDENT_BN254_XMD:SHA-256_H3_"
+	bfFullIdentH4DST = "BF01_FULLIDENT_BN254_XMD:SHA-256_H4_"
+
+	// expand_message_xmd(SHA-256)单次输出的最大字节数: 255 * 32
+	expandMsgXmdMaxSize = 8160
+)
+
+// BFIBEFullIdentCiphertext 表示FullIdent模式的密文。
+// 密文由三个部分组成:
+//   - U: G1群上的元素,为g^r,其中r = H3(σ, M)
+//   - V: σ ⊕ H2(e(g1x, H(Id))^r),长度为BFIBESigmaSize
+//   - W: M ⊕ H4(σ),与明文等长
+type BFIBEFullIdentCiphertext struct {
+	U bn254.G1Affine
+	V []byte
+	W []byte
+}
+
+// EncryptFullIdent 使用FullIdent模式对消息进行加密。
+// 与Encrypt不同,密文的任何改动都会在解密时被发现,消息长度没有限制。
+//
+// 参数:
+//   - identity: 接收者的身份标识符
+//   - message: 要加密的明文消息(字节数组)
+//
+// 返回值:
+//   - *BFIBEFullIdentCiphertext: 加密后的密文(U, V, W)
+//   - error: 如果加密过程失败,返回错误信息
+func (sender *BFIBESender) EncryptFullIdent(identity *BFIBEIdentity, message *BFIBEMessage) (*BFIBEFullIdentCiphertext, error) {
+	// qid = hashToCurve(id) in G2
+	qid, err := bn254.HashToG2([]byte(identity.Id), sender.publicParams.DST)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt message: %v", err)
+	}
+
+	// σ <- {0,1}^256
+	sigma := make([]byte, BFIBESigmaSize)
+	if _, err := rand.Read(sigma); err != nil {
+		return nil, fmt.Errorf("failed to encrypt message: %v", err)
+	}
+	// r = H3(σ, M)
+	r, err := bfFullIdentH3(sigma, message.Message)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt message: %v", err)
+	}
+	// U = g^r
+	u := *new(bn254.G1Affine).ScalarMultiplicationBase(r)
+
+	// V = σ xor H2(gid), gid = e(g^x, qid)^r
+	eGxQid, err := bn254.Pair([]bn254.G1Affine{sender.publicParams.G1x}, []bn254.G2Affine{qid})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt message: %v", err)
+	}
+	gid := *(new(bn254.GT).Exp(eGxQid, r))
+	h2, err := bfFullIdentH2(gid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt message: %v", err)
+	}
+	// W = M xor H4(σ)
+	h4, err := bfFullIdentH4(sigma, len(message.Message))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt message: %v", err)
+	}
+
+	return &BFIBEFullIdentCiphertext{
+		U: u,
+		V: utils.Xor(sigma, h2),
+		W: utils.Xor(message.Message, h4),
+	}, nil
+}
+
+// DecryptFullIdent 使用私钥对FullIdent模式的密文进行解密。
+// 解密后重新计算r = H3(σ, M)并检查U = g^r,被篡改或格式错误的密文返回错误。
+//
+// 参数:
+//   - ciphertext: 要解密的FullIdent密文
+//
+// 返回值:
+//   - *BFIBEMessage: 解密后的明文消息(字节数组)
+//   - error: 如果密文无效或解密失败,返回错误信息
+func (recipient *BFIBERecipient) DecryptFullIdent(ciphertext *BFIBEFullIdentCiphertext) (*BFIBEMessage, error) {
+	if len(ciphertext.V) != BFIBESigmaSize {
+		return nil, fmt.Errorf("failed to decrypt message: V must be %d bytes, got %d", BFIBESigmaSize, len(ciphertext.V))
+	}
+	if ciphertext.U.IsInfinity() || !ciphertext.U.IsOnCurve() || !ciphertext.U.IsInSubGroup() {
+		return nil, fmt.Errorf("failed to decrypt message: U is not a valid G1 element")
+	}
+
+	// σ = V xor H2(e(U, sk))
+	gid, err := bn254.Pair([]bn254.G1Affine{ciphertext.U}, []bn254.G2Affine{recipient.secretKey.sk})
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt message: %v", err)
+	}
+	h2, err := bfFullIdentH2(gid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt message: %v", err)
+	}
+	sigma := utils.Xor(ciphertext.V, h2)
+
+	// M = W xor H4(σ)
+	h4, err := bfFullIdentH4(sigma, len(ciphertext.W))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt message: %v", err)
+	}
+	message := utils.Xor(ciphertext.W, h4)
+
+	// r = H3(σ, M), U =?= g^r
+	r, err := bfFullIdentH3(sigma, message)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt message: %v", err)
+	}
+	expectedU := *new(bn254.G1Affine).ScalarMultiplicationBase(r)
+	if !expectedU.Equal(&ciphertext.U) {
+		return nil, fmt.Errorf("failed to decrypt message: invalid ciphertext")
+	}
+	return &BFIBEMessage{
+		Message: message,
+	}, nil
+}
+
+// EncryptFullIdent 使用publicParams中的g1x与DST以FullIdent模式加密消息,等价于BFIBESender.EncryptFullIdent。
+func (instance *BFIBEInstance) EncryptFullIdent(identity *BFIBEIdentity, message *BFIBEMessage, publicParams *BFIBEPublicParams) (*BFIBEFullIdentCiphertext, error) {
+	sender, err := NewBFIBESender(publicParams)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt message: %v", err)
+	}
+	return sender.EncryptFullIdent(identity, message)
+}
+
+// DecryptFullIdent 使用私钥对FullIdent模式的密文进行解密,等价于BFIBERecipient.DecryptFullIdent。
+func (instance *BFIBEInstance) DecryptFullIdent(ciphertext *BFIBEFullIdentCiphertext, secretKey *BFIBESecretKey) (*BFIBEMessage, error) {
+	return newRecipient(secretKey).DecryptFullIdent(ciphertext)
+}
+
+// bfFullIdentH2 H2: GT -> {0,1}^256。
+func bfFullIdentH2(gid bn254.GT) ([]byte, error) {
+	gidBytes := gid.Bytes()
+	return hash.ExpandMsgXmd(gidBytes[:], []byte(bfFullIdentH2DST), BFIBESigmaSize)
+}
+
+// bfFullIdentH3 H3: {0,1}^256 × {0,1}^* -> Zq。
+func bfFullIdentH3(sigma []byte, message []byte) (*big.Int, error) {
+	elements, err := fr.Hash(append(append([]byte{}, sigma...), message...), []byte(bfFullIdentH3DST), 1)
+	if err != nil {
+		return nil, err
+	}
+	return elements[0].BigInt(new(big.Int)), nil
+}
+
+// bfFullIdentH4 H4: {0,1}^256 -> {0,1}^{8*length}。
+// expand_message_xmd单次最多输出8160字节,更长的输出按块计算,第i块的输入为σ || i(4字节大端)。
+// gnark-crypto的ExpandMsgXmd要求输出至少32字节,因此每块的输出长度向上取整到32的倍数后再截断。
+func bfFullIdentH4(sigma []byte, length int) ([]byte, error) {
+	output := make([]byte, 0, length+sha256.Size)
+	for block := uint32(0); len(output) < length; block++ {
+		size := (length - len(output) + sha256.Size - 1) / sha256.Size * sha256.Size
+		if size > expandMsgXmdMaxSize {
+			size = expandMsgXmdMaxSize
+		}
+		input := binary.BigEndian.AppendUint32(append([]byte{}, sigma...), block)
+		expanded, err := hash.ExpandMsgXmd(input, []byte(bfFullIdentH4DST), size)
+		if err != nil {
+			return nil, err
+		}
+		output = append(output, expanded...)
+	}
+	return output[:length], nil
+}
@@ -0,0 +1,106 @@
+package ibe
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestBFIBEFullIdent 测试FullIdent模式的加密解密,包括超过GT编码长度与expand_message_xmd单次输出上限的长消息。
+func TestBFIBEFullIdent(t *testing.T) {
+	identity := &BFIBEIdentity{Id: "ChenBerry"}
+	instance, err := NewBFIBEInstance()
+	if err != nil {
+		t.Fatal("创建IBE实例失败:", err)
+	}
+	publicParams, err := instance.SetUp()
+	if err != nil {
+		t.Fatal("系统初始化失败:", err)
+	}
+	secretKey, err := instance.KeyGenerate(identity)
+	if err != nil {
+		t.Fatal("密钥生成失败:", err)
+	}
+
+	for _, length := range []int{0, 11, 1000, 20000} {
+		message := &BFIBEMessage{Message: bytes.Repeat([]byte("hajimi"), length/6+1)[:length]}
+		ciphertext, err := instance.EncryptFullIdent(identity, message, publicParams)
+		if err != nil {
+			t.Fatal("加密失败:", err)
+		}
+		decryptedMessage, err := instance.DecryptFullIdent(ciphertext, secretKey)
+		if err != nil {
+			t.Fatal("解密失败:", err)
+		}
+		if !bytes.Equal(decryptedMessage.Message, message.Message) {
+			t.Fatalf("长度为%d的消息解密错误", length)
+		}
+	}
+
+	// 其他身份的私钥无法解密
+	otherKey, err := instance.KeyGenerate(&BFIBEIdentity{Id: "Alice"})
+	if err != nil {
+		t.Fatal("密钥生成失败:", err)
+	}
+	ciphertext, err := instance.EncryptFullIdent(identity, &BFIBEMessage{Message: []byte("Hello World")}, publicParams)
+	if err != nil {
+		t.Fatal("加密失败:", err)
+	}
+	if _, err := instance.DecryptFullIdent(ciphertext, otherKey); err == nil {
+		t.Fatal("错误的私钥解密应当失败")
+	}
+}
+
+// TestBFIBEFullIdentTampered 测试被篡改的FullIdent密文在解密时被拒绝。
+func TestBFIBEFullIdentTampered(t *testing.T) {
+	identity := &BFIBEIdentity{Id: "ChenBerry"}
+	instance, err := NewBFIBEInstance()
+	if err != nil {
+		t.Fatal("创建IBE实例失败:", err)
+	}
+	publicParams, err := instance.SetUp()
+	if err != nil {
+		t.Fatal("系统初始化失败:", err)
+	}
+	secretKey, err := instance.KeyGenerate(identity)
+	if err != nil {
+		t.Fatal("密钥生成失败:", err)
+	}
+	ciphertext, err := instance.EncryptFullIdent(identity, &BFIBEMessage{Message: []byte("pay 100 to Bob")}, publicParams)
+	if err != nil {
+		t.Fatal("加密失败:", err)
+	}
+
+	// 翻转W的比特: BasicIdent下会得到翻转后的明文,FullIdent下解密失败
+	flippedW := *ciphertext
+	flippedW.W = append([]byte{}, ciphertext.W...)
+	flippedW.W[4] ^= 0x01
+	if _, err := instance.DecryptFullIdent(&flippedW, secretKey); err == nil {
+		t.Fatal("篡改W的密文应当被拒绝")
+	}
+
+	flippedV := *ciphertext
+	flippedV.V = append([]byte{}, ciphertext.V...)
+	flippedV.V[0] ^= 0x80
+	if _, err := instance.DecryptFullIdent(&flippedV, secretKey); err == nil {
+		t.Fatal("篡改V的密文应当被拒绝")
+	}
+
+	truncatedV := *ciphertext
+	truncatedV.V = ciphertext.V[:BFIBESigmaSize-1]
+	if _, err := instance.DecryptFullIdent(&truncatedV, secretKey); err == nil {
+		t.Fatal("V长度错误的密文应当被拒绝")
+	}
+
+	otherU := *ciphertext
+	otherU.U.Double(&ciphertext.U)
+	if _, err := instance.DecryptFullIdent(&otherU, secretKey); err == nil {
+		t.Fatal("替换U的密文应当被拒绝")
+	}
+
+	infinityU := *ciphertext
+	infinityU.U.X.SetZero()
+	infinityU.U.Y.SetZero()
+	if _, err := instance.DecryptFullIdent(&infinityU, secretKey); err == nil {
+		t.Fatal("U为无穷远点的密文应当被拒绝")
+	}
+}
@@ -0,0 +1,146 @@
+package ibe
+
+// Boneh-Franklin混合加密(KEM/DEM)
+//
+// Encrypt直接用gid = e(g^x, Qid)^r的384字节编码作为异或掩码,超出部分无法加密,密文也没有完整性保护。
+// 混合模式把gid作为密钥封装(KEM)的共享秘密,再用认证加密(DEM)加密任意长度的消息:
+//   - 加密: C1 = g^r,(k, nonce) = HKDF-SHA256(salt = 域分隔标签, IKM = gid, info = C1),C2 = AES-256-GCM(k, nonce, M)
+//   - 解密: gid = e(C1, sk),重新导出(k, nonce)并认证解密C2,认证失败(密文被篡改或私钥错误)时返回错误
+//
+// 每次加密的r都是新选取的,导出的密钥只使用一次,因此nonce可以与密钥一起由HKDF导出。
+// C1作为HKDF的info参与密钥导出,替换C1会导致解密失败。
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/mmsyan/GnarkPairingProject/utils"
+)
+
+const (
+	bfHybridHKDFSalt  = "BF01_HYBRID_BN254_HKDF-SHA256_AES-256-GCM_"
+	bfHybridKeySize   = 32
+	bfHybridNonceSize = 12
+)
+
+// BFIBEHybridCiphertext 表示混合加密模式的密文。
+// 密文由两个部分组成:
+//   - C1: G1群上的元素,为g^r,其中r是随机数
+//   - C2: AES-256-GCM密文,比明文多16字节的认证标签
+type BFIBEHybridCiphertext struct {
+	C1 bn254.G1Affine
+	C2 []byte
+}
+
+// EncryptHybrid 使用混合加密模式对任意长度的消息进行加密。
+// 对称密钥由gid = e(g1x, Qid)^r经HKDF导出,消息使用AES-256-GCM加密。
+//
+// 参数:
+//   - identity: 接收者的身份标识符
+//   - message: 要加密的明文消息(字节数组)
+//
+// 返回值:
+//   - *BFIBEHybridCiphertext: 加密后的密文,包含C1(G1元素)和C2(认证加密的字节数组)
+//   - error: 如果加密过程失败,返回错误信息
+func (sender *BFIBESender) EncryptHybrid(identity *BFIBEIdentity, message *BFIBEMessage) (*BFIBEHybridCiphertext, error) {
+	// qid = hashToCurve(id) in G2
+	qid, err := bn254.HashToG2([]byte(identity.Id), sender.publicParams.DST)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt message: %v", err)
+	}
+
+	// r <- Zq
+	r, err := rand.Int(rand.Reader, ecc.BN254.ScalarField())
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt message: %v", err)
+	}
+	// c1 = g^r
+	c1 := *new(bn254.G1Affine).ScalarMultiplicationBase(r)
+
+	// gid = e(g^x, qid)^r
+	eGxQid, err := bn254.Pair([]bn254.G1Affine{sender.publicParams.G1x}, []bn254.G2Affine{qid})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt message: %v", err)
+	}
+	gid := *(new(bn254.GT).Exp(eGxQid, r))
+
+	// c2 = AES-GCM(HKDF(gid, c1), m)
+	aead, nonce, err := bfHybridAEAD(gid, c1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt message: %v", err)
+	}
+	return &BFIBEHybridCiphertext{
+		C1: c1,
+		C2: aead.Seal(nil, nonce, message.Message, nil),
+	}, nil
+}
+
+// DecryptHybrid 使用私钥对混合加密模式的密文进行解密。
+// 通过配对运算恢复gid = e(C1, sk),导出对称密钥后认证解密C2。
+// 密文被篡改或者私钥与身份不匹配时认证失败,返回错误。C1必须是G1群中的非零元素。
+//
+// 参数:
+//   - ciphertext: 要解密的混合加密密文
+//
+// 返回值:
+//   - *BFIBEMessage: 解密后的明文消息(字节数组)
+//   - error: 如果密文无效或解密失败,返回错误信息
+func (recipient *BFIBERecipient) DecryptHybrid(ciphertext *BFIBEHybridCiphertext) (*BFIBEMessage, error) {
+	// C1为无穷远点时gid = 1,对称密钥只由公开数据导出,任何人都可以构造能够解密的密文
+	if ciphertext.C1.IsInfinity() || !ciphertext.C1.IsOnCurve() || !ciphertext.C1.IsInSubGroup() {
+		return nil, fmt.Errorf("failed to decrypt message: C1 is not a valid G1 element")
+	}
+	// gid = e(c1, sk) = e(g^r, qid^x)
+	gid, err := bn254.Pair([]bn254.G1Affine{ciphertext.C1}, []bn254.G2Affine{recipient.secretKey.sk})
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt message: %v", err)
+	}
+	aead, nonce, err := bfHybridAEAD(gid, ciphertext.C1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt message: %v", err)
+	}
+	message, err := aead.Open(nil, nonce, ciphertext.C2, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt message: %v", err)
+	}
+	return &BFIBEMessage{
+		Message: message,
+	}, nil
+}
+
+// EncryptHybrid 使用publicParams中的g1x与DST以混合加密模式加密消息,等价于BFIBESender.EncryptHybrid。
+func (instance *BFIBEInstance) EncryptHybrid(identity *BFIBEIdentity, message *BFIBEMessage, publicParams *BFIBEPublicParams) (*BFIBEHybridCiphertext, error) {
+	sender, err := NewBFIBESender(publicParams)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt message: %v", err)
+	}
+	return sender.EncryptHybrid(identity, message)
+}
+
+// DecryptHybrid 使用私钥对混合加密模式的密文进行解密,等价于BFIBERecipient.DecryptHybrid。
+func (instance *BFIBEInstance) DecryptHybrid(ciphertext *BFIBEHybridCiphertext, secretKey *BFIBESecretKey) (*BFIBEMessage, error) {
+	return newRecipient(secretKey).DecryptHybrid(ciphertext)
+}
+
+// bfHybridAEAD 由gid和C1导出AES-256-GCM的密钥与nonce: HKDF-Expand(HKDF-Extract(salt, gid), C1, 32 + 12)。
+func bfHybridAEAD(gid bn254.GT, c1 bn254.G1Affine) (cipher.AEAD, []byte, error) {
+	gidBytes := utils.Hash2(gid)
+	c1Bytes := c1.Bytes()
+	prk := utils.HKDFExtract([]byte(bfHybridHKDFSalt), gidBytes)
+	okm, err := utils.HKDFExpand(prk, c1Bytes[:], bfHybridKeySize+bfHybridNonceSize)
+	if err != nil {
+		return nil, nil, err
+	}
+	block, err := aes.NewCipher(okm[:bfHybridKeySize])
+	if err != nil {
+		return nil, nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, nil, err
+	}
+	return aead, okm[bfHybridKeySize:], nil
+}
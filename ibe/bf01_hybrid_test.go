@@ -0,0 +1,89 @@
+package ibe
+
+import (
+	"bytes"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"testing"
+)
+
+// TestBFIBEHybrid 测试混合加密模式对不同长度消息的加密解密,以及错误私钥、被篡改密文的拒绝。
+func TestBFIBEHybrid(t *testing.T) {
+	identity := &BFIBEIdentity{Id: "ChenBerry"}
+	instance, err := NewBFIBEInstance()
+	if err != nil {
+		t.Fatal("创建IBE实例失败:", err)
+	}
+	publicParams, err := instance.SetUp()
+	if err != nil {
+		t.Fatal("系统初始化失败:", err)
+	}
+	secretKey, err := instance.KeyGenerate(identity)
+	if err != nil {
+		t.Fatal("密钥生成失败:", err)
+	}
+
+	for _, length := range []int{0, 11, BFIBEMaxMessageSize, BFIBEMaxMessageSize + 1, 100000} {
+		message := &BFIBEMessage{Message: bytes.Repeat([]byte("hajimi"), length/6+1)[:length]}
+		ciphertext, err := instance.EncryptHybrid(identity, message, publicParams)
+		if err != nil {
+			t.Fatal("加密失败:", err)
+		}
+		decryptedMessage, err := instance.DecryptHybrid(ciphertext, secretKey)
+		if err != nil {
+			t.Fatal("解密失败:", err)
+		}
+		if !bytes.Equal(decryptedMessage.Message, message.Message) {
+			t.Fatalf("长度为%d的消息解密错误", length)
+		}
+	}
+
+	ciphertext, err := instance.EncryptHybrid(identity, &BFIBEMessage{Message: []byte("pay 100 to Bob")}, publicParams)
+	if err != nil {
+		t.Fatal("加密失败:", err)
+	}
+
+	// 其他身份的私钥无法解密
+	otherKey, err := instance.KeyGenerate(&BFIBEIdentity{Id: "Alice"})
+	if err != nil {
+		t.Fatal("密钥生成失败:", err)
+	}
+	if _, err := instance.DecryptHybrid(ciphertext, otherKey); err == nil {
+		t.Fatal("错误的私钥解密应当失败")
+	}
+
+	// 翻转C2的比特
+	flippedC2 := *ciphertext
+	flippedC2.C2 = append([]byte{}, ciphertext.C2...)
+	flippedC2.C2[0] ^= 0x01
+	if _, err := instance.DecryptHybrid(&flippedC2, secretKey); err == nil {
+		t.Fatal("篡改C2的密文应当被拒绝")
+	}
+
+	// 截断认证标签
+	truncatedC2 := *ciphertext
+	truncatedC2.C2 = ciphertext.C2[:len(ciphertext.C2)-1]
+	if _, err := instance.DecryptHybrid(&truncatedC2, secretKey); err == nil {
+		t.Fatal("截断的密文应当被拒绝")
+	}
+
+	// 替换C1
+	otherC1 := *ciphertext
+	otherC1.C1.Double(&ciphertext.C1)
+	if _, err := instance.DecryptHybrid(&otherC1, secretKey); err == nil {
+		t.Fatal("替换C1的密文应当被拒绝")
+	}
+
+	// C1为无穷远点: 任何人都可以用gid = 1导出的密钥构造密文,必须拒绝
+	var infinity bn254.G1Affine
+	forgedAEAD, forgedNonce, err := bfHybridAEAD(*new(bn254.GT).SetOne(), infinity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged := &BFIBEHybridCiphertext{
+		C1: infinity,
+		C2: forgedAEAD.Seal(nil, forgedNonce, []byte("forged"), nil),
+	}
+	if _, err := instance.DecryptHybrid(forged, secretKey); err == nil {
+		t.Fatal("C1为无穷远点的密文应当被拒绝")
+	}
+}
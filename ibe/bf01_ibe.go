@@ -10,15 +10,17 @@ package ibe
 // 预印本: https://crypto.stanford.edu/~dabo/pubs/papers/bfibe.pdf
 //
 // 该实现基于BN254椭圆曲线和配对运算,提供了完整的Boneh-Franklin IBE系统功能,包括:
-//   - 系统初始化(SetUp)
-//   - 密钥生成(KeyGenerate)
-//   - 加密(Encrypt)
-//   - 解密(Decrypt)
+//   - 密钥生成中心BFIBEPKG: 持有主密钥,系统初始化(Setup)并为用户提取私钥(Extract)
+//   - 发送者BFIBESender: 只根据公开的公共参数加密
+//   - 接收者BFIBERecipient: 只根据自己的私钥解密
+//
+// BFIBEInstance同时持有主密钥并提供SetUp/KeyGenerate/Encrypt/Decrypt,保留用于兼容,
+// 加密与解密分别委托给BFIBESender与BFIBERecipient。
 //
 // 与Boneh-Boyen方案的主要区别:
 //   - 使用Hash-to-Curve将身份映射到G2群元素
-//   - 采用混合加密方式,使用XOR掩码保护实际消息
-//   - 密文更加紧凑,适合加密任意长度的字节消息
+//   - 采用混合加密方式,使用XOR掩码保护实际消息,掩码为GT元素的编码(BFIBEMaxMessageSize字节)
+//   - 任意长度的消息使用EncryptHybrid(HKDF + AES-GCM),需要CCA安全时使用EncryptFullIdent
 
 import (
 	"crypto/rand"
@@ -29,6 +31,14 @@ import (
 	"math/big"
 )
 
+const (
+	// BFIBEMaxMessageSize 为掩码H2(gid) = gid.Bytes()的长度,更长的消息应使用EncryptHybrid加密。
+	BFIBEMaxMessageSize = bn254.SizeOfGT
+
+	// BFIBEDefaultDST 将身份映射到G2群时默认使用的域分离标签。
+	BFIBEDefaultDST = "ibe Encryption"
+)
+
 // BFIBEInstance 表示Boneh-Franklin身份基加密(IBE)方案的实例对象。
 // 该实例包含了系统的主密钥x,它是Zp域上的一个随机元素。
 // 主密钥用于生成用户的私钥,必须严格保密。
@@ -40,10 +50,12 @@ type BFIBEInstance struct {
 
 // BFIBEPublicParams 表示Boneh-Franklin IBE方案的公共参数。
 // 这些参数在系统初始化时生成,可以公开发布给所有用户。
-// 包含基础生成元g1和由主密钥派生的公开元素g1x=g1^x。
+// 包含基础生成元G1、由主密钥派生的公开元素G1x=G1^x,以及将身份映射到G2群时使用的域分离标签DST。
+// 字段均为导出字段,可以直接序列化后分发给发送者。
 type BFIBEPublicParams struct {
-	g1  bn254.G1Affine
-	g1x bn254.G1Affine
+	G1  bn254.G1Affine
+	G1x bn254.G1Affine
+	DST []byte
 }
 
 // BFIBEIdentity 表示Boneh-Franklin IBE方案中的用户身份。
@@ -90,7 +102,7 @@ func NewBFIBEInstance() (*BFIBEInstance, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate identity based encryption instance")
 	}
-	return &BFIBEInstance{x, []byte("ibe Encryption")}, nil
+	return &BFIBEInstance{x, []byte(BFIBEDefaultDST)}, nil
 }
 
 // SetUp 执行系统初始化操作,生成并返回公共参数。
@@ -98,17 +110,10 @@ func NewBFIBEInstance() (*BFIBEInstance, error) {
 // 生成的公共参数可以安全地发布给所有系统用户,用于加密操作。
 //
 // 返回值:
-//   - *BFIBEPublicParams: 系统公共参数,包含g1和g1^x
+//   - *BFIBEPublicParams: 系统公共参数,包含g1、g1^x和DST
 //   - error: 如果初始化失败,返回错误信息
 func (instance *BFIBEInstance) SetUp() (*BFIBEPublicParams, error) {
-	// g <- G1
-	// g^x in G1
-	_, _, g, _ := bn254.Generators()
-	gx := *new(bn254.G1Affine).ScalarMultiplicationBase(instance.x)
-	return &BFIBEPublicParams{
-		g1:  g,
-		g1x: gx,
-	}, nil
+	return bfSetUp(instance.x, instance.DST), nil
 }
 
 // KeyGenerate 为指定用户身份生成私钥。
@@ -122,16 +127,7 @@ func (instance *BFIBEInstance) SetUp() (*BFIBEPublicParams, error) {
 //   - *BFIBESecretKey: 生成的私钥,为G2群上的元素
 //   - error: 如果Hash-to-Curve或密钥生成失败,返回错误信息
 func (instance *BFIBEInstance) KeyGenerate(identity *BFIBEIdentity) (*BFIBESecretKey, error) {
-	// qid = hashToCurve(id) in G2
-	qid, err := bn254.HashToG2([]byte(identity.Id), instance.DST)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate key")
-	}
-	// sk = qid^x
-	sk := *new(bn254.G2Affine).ScalarMultiplication(&qid, instance.x)
-	return &BFIBESecretKey{
-		sk: sk,
-	}, nil
+	return bfExtract(instance.x, instance.DST, identity)
 }
 
 // Encrypt 使用指定用户身份对消息进行加密。
@@ -143,18 +139,18 @@ func (instance *BFIBEInstance) KeyGenerate(identity *BFIBEIdentity) (*BFIBESecre
 //
 // 任何知道公共参数的用户都可以使用接收者的身份进行加密,
 // 而无需事先获取接收者的公钥证书。
+// 掩码H2(gid)只有BFIBEMaxMessageSize字节,更长的消息应使用EncryptHybrid;密文不提供完整性保护。
 //
 // 参数:
 //   - identity: 接收者的身份标识符
 //   - message: 要加密的明文消息(字节数组)
-//   - publicParams: 系统公共参数
 //
 // 返回值:
 //   - *BFIBECiphertext: 加密后的密文,包含C1(G1元素)和C2(字节数组)
 //   - error: 如果加密过程失败,返回错误信息
-func (instance *BFIBEInstance) Encrypt(identity *BFIBEIdentity, message *BFIBEMessage, publicParams *BFIBEPublicParams) (*BFIBECiphertext, error) {
+func (sender *BFIBESender) Encrypt(identity *BFIBEIdentity, message *BFIBEMessage) (*BFIBECiphertext, error) {
 	// qid = hashToCurve(id) in G2
-	qid, err := bn254.HashToG2([]byte(identity.Id), instance.DST)
+	qid, err := bn254.HashToG2([]byte(identity.Id), sender.publicParams.DST)
 	if err != nil {
 		return nil, fmt.Errorf("failed to encrypt message")
 	}
@@ -169,7 +165,7 @@ func (instance *BFIBEInstance) Encrypt(identity *BFIBEIdentity, message *BFIBEMe
 
 	// c2 = m xor H2(gid)
 	// gid = e(g^x, qid)^r
-	eGxQid, err := bn254.Pair([]bn254.G1Affine{publicParams.g1x}, []bn254.G2Affine{qid})
+	eGxQid, err := bn254.Pair([]bn254.G1Affine{sender.publicParams.G1x}, []bn254.G2Affine{qid})
 	if err != nil {
 		return nil, fmt.Errorf("failed to encrypt message")
 	}
@@ -193,14 +189,13 @@ func (instance *BFIBEInstance) Encrypt(identity *BFIBEIdentity, message *BFIBEMe
 //
 // 参数:
 //   - ciphertext: 要解密的密文
-//   - secretKey: 用户的私钥
 //
 // 返回值:
 //   - *BFIBEMessage: 解密后的明文消息(字节数组)
 //   - error: 如果解密失败,返回错误信息
-func (instance *BFIBEInstance) Decrypt(ciphertext *BFIBECiphertext, secretKey *BFIBESecretKey) (*BFIBEMessage, error) {
+func (recipient *BFIBERecipient) Decrypt(ciphertext *BFIBECiphertext) (*BFIBEMessage, error) {
 	// gid = e(c1, sk) = e(g^r, qid^x)
-	gid, err := bn254.Pair([]bn254.G1Affine{ciphertext.C1}, []bn254.G2Affine{secretKey.sk})
+	gid, err := bn254.Pair([]bn254.G1Affine{ciphertext.C1}, []bn254.G2Affine{recipient.secretKey.sk})
 	if err != nil {
 		return nil, fmt.Errorf("failed to decrypt message")
 	}
@@ -209,3 +204,17 @@ func (instance *BFIBEInstance) Decrypt(ciphertext *BFIBECiphertext, secretKey *B
 		Message: utils.Xor(ciphertext.C2, gidBytes),
 	}, nil
 }
+
+// Encrypt 使用publicParams中的g1x与DST加密消息,等价于BFIBESender.Encrypt。
+func (instance *BFIBEInstance) Encrypt(identity *BFIBEIdentity, message *BFIBEMessage, publicParams *BFIBEPublicParams) (*BFIBECiphertext, error) {
+	sender, err := NewBFIBESender(publicParams)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt message: %v", err)
+	}
+	return sender.Encrypt(identity, message)
+}
+
+// Decrypt 使用私钥对密文进行解密,等价于BFIBERecipient.Decrypt。
+func (instance *BFIBEInstance) Decrypt(ciphertext *BFIBECiphertext, secretKey *BFIBESecretKey) (*BFIBEMessage, error) {
+	return newRecipient(secretKey).Decrypt(ciphertext)
+}
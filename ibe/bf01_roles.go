@@ -0,0 +1,171 @@
+package ibe
+
+// Boneh-Franklin IBE的三个角色
+//
+// BFIBEInstance同时持有主密钥x并提供加密,发送者必须拿到持有主密钥的对象才能加密。这里将方案拆分为:
+//   - BFIBEPKG: 密钥生成中心,持有主密钥,生成公共参数(Setup)并为用户提取私钥(Extract)
+//   - BFIBESender: 发送者,只由公开的BFIBEPublicParams构造,可以使用BasicIdent、混合加密与FullIdent三种模式加密
+//   - BFIBERecipient: 接收者,只由自己的BFIBESecretKey构造,解密对应模式的密文
+
+import (
+	"crypto/rand"
+	"fmt"
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"math/big"
+)
+
+// BFIBEPKG 表示Boneh-Franklin IBE方案的密钥生成中心(PKG)。
+// PKG持有主密钥x,必须严格保密;发送者与接收者都不需要访问该对象。
+type BFIBEPKG struct {
+	x   *big.Int
+	dst []byte
+}
+
+// BFIBESender 表示Boneh-Franklin IBE方案的发送者,只持有公共参数。
+type BFIBESender struct {
+	publicParams BFIBEPublicParams
+}
+
+// BFIBERecipient 表示Boneh-Franklin IBE方案的接收者,只持有自己的私钥。
+type BFIBERecipient struct {
+	secretKey BFIBESecretKey
+}
+
+// NewBFIBEPKG 创建使用默认域分离标签BFIBEDefaultDST的密钥生成中心,主密钥x从Zp中均匀随机采样。
+//
+// 返回值:
+//   - *BFIBEPKG: 持有主密钥的密钥生成中心
+//   - error: 如果生成主密钥失败,返回错误信息
+func NewBFIBEPKG() (*BFIBEPKG, error) {
+	return NewBFIBEPKGWithDST([]byte(BFIBEDefaultDST))
+}
+
+// NewBFIBEPKGWithDST 创建使用指定域分离标签的密钥生成中心,DST会包含在公共参数中。
+//
+// 参数:
+//   - dst: 将身份映射到G2群时使用的域分离标签,不能为空
+//
+// 返回值:
+//   - *BFIBEPKG: 持有主密钥的密钥生成中心
+//   - error: 如果DST为空或生成主密钥失败,返回错误信息
+func NewBFIBEPKGWithDST(dst []byte) (*BFIBEPKG, error) {
+	if len(dst) == 0 {
+		return nil, fmt.Errorf("failed to create PKG: empty DST")
+	}
+	x, err := rand.Int(rand.Reader, ecc.BN254.ScalarField())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create PKG: %v", err)
+	}
+	return &BFIBEPKG{
+		x:   x,
+		dst: append([]byte{}, dst...),
+	}, nil
+}
+
+// Setup 生成可以公开发布的系统公共参数(g1, g1^x, DST)。
+//
+// 返回值:
+//   - *BFIBEPublicParams: 系统公共参数
+//   - error: 如果初始化失败,返回错误信息
+func (pkg *BFIBEPKG) Setup() (*BFIBEPublicParams, error) {
+	return bfSetUp(pkg.x, pkg.dst), nil
+}
+
+// Extract 为指定用户身份提取私钥sk = H(Id)^x,私钥应通过安全信道传递给用户。
+//
+// 参数:
+//   - identity: 用户的身份标识符
+//
+// 返回值:
+//   - *BFIBESecretKey: 用户的私钥
+//   - error: 如果Hash-to-Curve失败,返回错误信息
+func (pkg *BFIBEPKG) Extract(identity *BFIBEIdentity) (*BFIBESecretKey, error) {
+	return bfExtract(pkg.x, pkg.dst, identity)
+}
+
+// NewBFIBESender 根据公共参数创建发送者。
+// 公共参数可能来自不可信的信道,创建时检查G1为G1群的生成元、G1x为G1群中的非零元素且DST不为空。
+//
+// 参数:
+//   - publicParams: 系统公共参数
+//
+// 返回值:
+//   - *BFIBESender: 发送者
+//   - error: 如果公共参数无效,返回错误信息
+func NewBFIBESender(publicParams *BFIBEPublicParams) (*BFIBESender, error) {
+	if err := validateBFIBEPublicParams(publicParams); err != nil {
+		return nil, fmt.Errorf("failed to create sender: %v", err)
+	}
+	return &BFIBESender{
+		publicParams: BFIBEPublicParams{
+			G1:  publicParams.G1,
+			G1x: publicParams.G1x,
+			DST: append([]byte{}, publicParams.DST...),
+		},
+	}, nil
+}
+
+// NewBFIBERecipient 根据用户私钥创建接收者,私钥必须是G2群中的非零元素。
+//
+// 参数:
+//   - secretKey: 用户的私钥
+//
+// 返回值:
+//   - *BFIBERecipient: 接收者
+//   - error: 如果私钥无效,返回错误信息
+func NewBFIBERecipient(secretKey *BFIBESecretKey) (*BFIBERecipient, error) {
+	if secretKey.sk.IsInfinity() || !secretKey.sk.IsOnCurve() || !secretKey.sk.IsInSubGroup() {
+		return nil, fmt.Errorf("failed to create recipient: secret key is not a valid G2 element")
+	}
+	return newRecipient(secretKey), nil
+}
+
+// validateBFIBEPublicParams 检查G1为G1群的生成元、G1x为G1群中的非零元素且DST不为空。
+func validateBFIBEPublicParams(publicParams *BFIBEPublicParams) error {
+	_, _, g, _ := bn254.Generators()
+	if !publicParams.G1.Equal(&g) {
+		return fmt.Errorf("G1 is not the generator of G1")
+	}
+	if publicParams.G1x.IsInfinity() || !publicParams.G1x.IsOnCurve() || !publicParams.G1x.IsInSubGroup() {
+		return fmt.Errorf("G1x is not a valid G1 element")
+	}
+	if len(publicParams.DST) == 0 {
+		return fmt.Errorf("empty DST")
+	}
+	return nil
+}
+
+// newRecipient 不检查私钥,直接构造接收者。
+func newRecipient(secretKey *BFIBESecretKey) *BFIBERecipient {
+	return &BFIBERecipient{
+		secretKey: *secretKey,
+	}
+}
+
+// bfSetUp 计算公共参数(g, g^x, DST)。
+func bfSetUp(x *big.Int, dst []byte) *BFIBEPublicParams {
+	// g <- G1
+	// g^x in G1
+	_, _, g, _ := bn254.Generators()
+	gx := *new(bn254.G1Affine).ScalarMultiplicationBase(x)
+	return &BFIBEPublicParams{
+		G1:  g,
+		G1x: gx,
+		DST: append([]byte{}, dst...),
+	}
+}
+
+// bfExtract 计算身份的私钥sk = H(Id)^x。
+func bfExtract(x *big.Int, dst []byte, identity *BFIBEIdentity) (*BFIBESecretKey, error) {
+	// qid = hashToCurve(id) in G2
+	qid, err := bn254.HashToG2([]byte(identity.Id), dst)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate key")
+	}
+	// sk = qid^x
+	sk := *new(bn254.G2Affine).ScalarMultiplication(&qid, x)
+	return &BFIBESecretKey{
+		sk: sk,
+	}, nil
+}
@@ -0,0 +1,139 @@
+package ibe
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+// TestBFIBERoles 测试发送者只根据序列化后的公共参数加密,接收者只根据私钥解密。
+func TestBFIBERoles(t *testing.T) {
+	pkg, err := NewBFIBEPKGWithDST([]byte("BF01 roles test"))
+	if err != nil {
+		t.Fatal("创建PKG失败:", err)
+	}
+	publicParams, err := pkg.Setup()
+	if err != nil {
+		t.Fatal("系统初始化失败:", err)
+	}
+
+	// 公共参数经过序列化分发给发送者
+	encoded, err := json.Marshal(publicParams)
+	if err != nil {
+		t.Fatal("序列化公共参数失败:", err)
+	}
+	var receivedParams BFIBEPublicParams
+	if err := json.Unmarshal(encoded, &receivedParams); err != nil {
+		t.Fatal("反序列化公共参数失败:", err)
+	}
+	sender, err := NewBFIBESender(&receivedParams)
+	if err != nil {
+		t.Fatal("创建发送者失败:", err)
+	}
+
+	identity := &BFIBEIdentity{Id: "ChenBerry"}
+	secretKey, err := pkg.Extract(identity)
+	if err != nil {
+		t.Fatal("密钥提取失败:", err)
+	}
+	recipient, err := NewBFIBERecipient(secretKey)
+	if err != nil {
+		t.Fatal("创建接收者失败:", err)
+	}
+
+	message := &BFIBEMessage{Message: []byte("Hello World")}
+	ciphertext, err := sender.Encrypt(identity, message)
+	if err != nil {
+		t.Fatal("加密失败:", err)
+	}
+	decryptedMessage, err := recipient.Decrypt(ciphertext)
+	if err != nil || !bytes.Equal(decryptedMessage.Message, message.Message) {
+		t.Fatal("BasicIdent解密错误:", err)
+	}
+
+	hybridCiphertext, err := sender.EncryptHybrid(identity, message)
+	if err != nil {
+		t.Fatal("加密失败:", err)
+	}
+	decryptedMessage, err = recipient.DecryptHybrid(hybridCiphertext)
+	if err != nil || !bytes.Equal(decryptedMessage.Message, message.Message) {
+		t.Fatal("混合加密解密错误:", err)
+	}
+
+	fullIdentCiphertext, err := sender.EncryptFullIdent(identity, message)
+	if err != nil {
+		t.Fatal("加密失败:", err)
+	}
+	decryptedMessage, err = recipient.DecryptFullIdent(fullIdentCiphertext)
+	if err != nil || !bytes.Equal(decryptedMessage.Message, message.Message) {
+		t.Fatal("FullIdent解密错误:", err)
+	}
+
+	// 使用其他DST的PKG提取的私钥无法解密
+	otherPKG, err := NewBFIBEPKG()
+	if err != nil {
+		t.Fatal("创建PKG失败:", err)
+	}
+	otherKey, err := otherPKG.Extract(identity)
+	if err != nil {
+		t.Fatal("密钥提取失败:", err)
+	}
+	otherRecipient, err := NewBFIBERecipient(otherKey)
+	if err != nil {
+		t.Fatal("创建接收者失败:", err)
+	}
+	if _, err := otherRecipient.DecryptHybrid(hybridCiphertext); err == nil {
+		t.Fatal("其他PKG的私钥解密应当失败")
+	}
+}
+
+// TestBFIBERolesInvalid 测试无效的公共参数与私钥被拒绝。
+func TestBFIBERolesInvalid(t *testing.T) {
+	pkg, err := NewBFIBEPKG()
+	if err != nil {
+		t.Fatal("创建PKG失败:", err)
+	}
+	publicParams, err := pkg.Setup()
+	if err != nil {
+		t.Fatal("系统初始化失败:", err)
+	}
+
+	noDST := *publicParams
+	noDST.DST = nil
+	if _, err := NewBFIBESender(&noDST); err == nil {
+		t.Fatal("DST为空的公共参数应当被拒绝")
+	}
+	infinityG1x := *publicParams
+	infinityG1x.G1x.X.SetZero()
+	infinityG1x.G1x.Y.SetZero()
+	if _, err := NewBFIBESender(&infinityG1x); err == nil {
+		t.Fatal("G1x为无穷远点的公共参数应当被拒绝")
+	}
+	otherG1 := *publicParams
+	otherG1.G1 = publicParams.G1x
+	if _, err := NewBFIBESender(&otherG1); err == nil {
+		t.Fatal("G1不是生成元的公共参数应当被拒绝")
+	}
+	if _, err := NewBFIBEPKGWithDST(nil); err == nil {
+		t.Fatal("DST为空的PKG应当被拒绝")
+	}
+	if _, err := NewBFIBERecipient(&BFIBESecretKey{}); err == nil {
+		t.Fatal("无穷远点私钥应当被拒绝")
+	}
+	// BFIBEInstance的加密方法同样检查公共参数
+	instance, err := NewBFIBEInstance()
+	if err != nil {
+		t.Fatal("创建实例失败:", err)
+	}
+	identity := &BFIBEIdentity{Id: "alice"}
+	message := &BFIBEMessage{Message: []byte("hello")}
+	if _, err := instance.Encrypt(identity, message, &noDST); err == nil {
+		t.Fatal("Encrypt应当拒绝DST为空的公共参数")
+	}
+	if _, err := instance.EncryptHybrid(identity, message, &infinityG1x); err == nil {
+		t.Fatal("EncryptHybrid应当拒绝G1x为无穷远点的公共参数")
+	}
+	if _, err := instance.EncryptFullIdent(identity, message, &otherG1); err == nil {
+		t.Fatal("EncryptFullIdent应当拒绝G1不是生成元的公共参数")
+	}
+}
@@ -0,0 +1,306 @@
+package ibe
+
+// Boneh-Franklin门限密钥生成中心(论文第6节"Distributed PKG")
+//
+// 单个PKG持有主密钥x,可以为任何身份提取私钥并解密所有密文。门限方案将x拆分给n个密钥服务器:
+//   - 服务器i持有分片x_i = f(i),f为常数项为x、次数为t-1的多项式,公开分片g^{x_i}
+//   - 用户向服务器i请求部分私钥d_i = H(Id)^{x_i},并检查e(g^{x_i}, H(Id)) = e(g, d_i)
+//   - 任意t个有效的部分私钥通过拉格朗日插值合并为普通私钥: sk = prod(d_i^{Delta_{i,S}(0)}) = H(Id)^x
+//
+// 公共参数g1x = g^x与单个PKG时相同,发送者不需要知道PKG是否被拆分。
+// 分片可以由可信分发者拆分现有主密钥得到(BFIBEPKG.Split、BFIBEInstance.Split),
+// 也可以由dkg包在没有可信分发者的情况下联合生成: 使用NewBFIBEKeyServerFromDKG与NewBFIBEThresholdParamsFromDKG
+// 将dkg.KeyShare转换为密钥服务器与门限公共参数。
+
+import (
+	"fmt"
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/mmsyan/GnarkPairingProject/dkg"
+	"github.com/mmsyan/GnarkPairingProject/utils"
+	"math/big"
+)
+
+// BFIBEThresholdParams 表示门限密钥生成中心的公共参数。
+//   - PublicParams: 与单个PKG相同的系统公共参数,G1x = g^x
+//   - Threshold: 合并私钥所需的部分私钥数量t
+//   - PublicShares: 各服务器的公开分片,PublicShares[i-1] = g^{x_i}
+type BFIBEThresholdParams struct {
+	PublicParams BFIBEPublicParams
+	Threshold    int
+	PublicShares []bn254.G1Affine
+}
+
+// BFIBEKeyServer 表示门限密钥生成中心中的第index个密钥服务器(下标从1开始),持有主密钥的分片x_i。
+type BFIBEKeyServer struct {
+	index int
+	share *big.Int
+	dst   []byte
+}
+
+// BFIBEPartialKey 表示第Index个密钥服务器为某一身份提取的部分私钥H(Id)^{x_i}。
+type BFIBEPartialKey struct {
+	Index int
+	Key   bn254.G2Affine
+}
+
+// Split 使用Shamir秘密共享将PKG的主密钥拆分给n个密钥服务器,任意t个服务器可以联合提取私钥。
+// 拆分后应销毁PKG,否则单个PKG仍然可以为任何身份提取私钥。
+//
+// 参数:
+//   - threshold: 合并私钥所需的服务器数量t,1 <= t <= n
+//   - n: 密钥服务器的数量
+//
+// 返回值:
+//   - *BFIBEThresholdParams: 门限公共参数,其中的PublicParams与Setup的输出相同
+//   - []*BFIBEKeyServer: n个密钥服务器,第i个服务器的下标为i + 1
+//   - error: 如果门限无效,返回错误信息
+func (pkg *BFIBEPKG) Split(threshold int, n int) (*BFIBEThresholdParams, []*BFIBEKeyServer, error) {
+	return bfSplit(pkg.x, pkg.dst, threshold, n)
+}
+
+// Split 与BFIBEPKG.Split相同,拆分实例的主密钥,拆分后应销毁实例。
+func (instance *BFIBEInstance) Split(threshold int, n int) (*BFIBEThresholdParams, []*BFIBEKeyServer, error) {
+	return bfSplit(instance.x, instance.DST, threshold, n)
+}
+
+// NewBFIBEKeyServer 使用已有的主密钥分片(例如DKG的输出)创建密钥服务器。
+//
+// 参数:
+//   - index: 服务器的下标,从1开始
+//   - share: 主密钥分片x_i,取值范围为[1, q)。x_i = 0时公开分片为无穷远点,无法验证部分私钥
+//   - dst: 将身份映射到G2群时使用的域分离标签,必须与公共参数中的DST一致
+//
+// 返回值:
+//   - *BFIBEKeyServer: 密钥服务器
+//   - error: 如果参数无效,返回错误信息
+func NewBFIBEKeyServer(index int, share *big.Int, dst []byte) (*BFIBEKeyServer, error) {
+	if index < 1 {
+		return nil, fmt.Errorf("failed to create key server: invalid index %d", index)
+	}
+	if share == nil || share.Sign() <= 0 || share.Cmp(ecc.BN254.ScalarField()) >= 0 {
+		return nil, fmt.Errorf("failed to create key server: share is out of range")
+	}
+	if len(dst) == 0 {
+		return nil, fmt.Errorf("failed to create key server: empty DST")
+	}
+	return &BFIBEKeyServer{
+		index: index,
+		share: new(big.Int).Set(share),
+		dst:   append([]byte{}, dst...),
+	}, nil
+}
+
+// NewBFIBEThresholdParams 根据系统公共参数与各服务器的公开分片(例如DKG的群公钥与验证密钥)创建门限公共参数。
+// 公共参数与公开分片检查为有效的G1元素,1 <= threshold <= len(publicShares)。
+func NewBFIBEThresholdParams(publicParams *BFIBEPublicParams, threshold int, publicShares []bn254.G1Affine) (*BFIBEThresholdParams, error) {
+	if err := validateBFIBEPublicParams(publicParams); err != nil {
+		return nil, fmt.Errorf("failed to create threshold params: %v", err)
+	}
+	if threshold < 1 || threshold > len(publicShares) {
+		return nil, fmt.Errorf("failed to create threshold params: invalid threshold %d of %d", threshold, len(publicShares))
+	}
+	for i, publicShare := range publicShares {
+		if publicShare.IsInfinity() || !publicShare.IsOnCurve() || !publicShare.IsInSubGroup() {
+			return nil, fmt.Errorf("failed to create threshold params: public share %d is not a valid G1 element", i+1)
+		}
+	}
+	return &BFIBEThresholdParams{
+		PublicParams: BFIBEPublicParams{
+			G1:  publicParams.G1,
+			G1x: publicParams.G1x,
+			DST: append([]byte{}, publicParams.DST...),
+		},
+		Threshold:    threshold,
+		PublicShares: append([]bn254.G1Affine{}, publicShares...),
+	}, nil
+}
+
+// NewBFIBEKeyServerFromDKG 使用DKG的输出创建第share.Index个密钥服务器,主密钥分片为share.Share。
+//
+// 参数:
+//   - share: 该服务器在DKG中得到的dkg.KeyShare
+//   - dst: 将身份映射到G2群时使用的域分离标签,必须与NewBFIBEThresholdParamsFromDKG使用的DST一致
+//
+// 返回值:
+//   - *BFIBEKeyServer: 密钥服务器
+//   - error: 如果分片无效,返回错误信息
+func NewBFIBEKeyServerFromDKG(share *dkg.KeyShare, dst []byte) (*BFIBEKeyServer, error) {
+	if share == nil {
+		return nil, fmt.Errorf("failed to create key server: nil key share")
+	}
+	return NewBFIBEKeyServer(share.Index, share.Share, dst)
+}
+
+// NewBFIBEThresholdParamsFromDKG 使用DKG的输出创建门限公共参数:
+// G1x为群公钥GroupPublicKey,门限为Threshold,公开分片为验证密钥VerificationKeys。
+// 所有参与方得到的这三项相同,可以使用任意一个参与方的dkg.KeyShare。
+func NewBFIBEThresholdParamsFromDKG(share *dkg.KeyShare, dst []byte) (*BFIBEThresholdParams, error) {
+	if share == nil {
+		return nil, fmt.Errorf("failed to create threshold params: nil key share")
+	}
+	_, _, g, _ := bn254.Generators()
+	return NewBFIBEThresholdParams(&BFIBEPublicParams{
+		G1:  g,
+		G1x: share.GroupPublicKey,
+		DST: dst,
+	}, share.Threshold, share.VerificationKeys)
+}
+
+// Index 返回服务器的下标。
+func (server *BFIBEKeyServer) Index() int {
+	return server.index
+}
+
+// PublicShare 返回服务器的公开分片g^{x_i}。
+func (server *BFIBEKeyServer) PublicShare() bn254.G1Affine {
+	return *new(bn254.G1Affine).ScalarMultiplicationBase(server.share)
+}
+
+// Extract 为指定身份提取部分私钥d_i = H(Id)^{x_i}。
+// 服务器应在提取前认证请求者确实拥有该身份。
+func (server *BFIBEKeyServer) Extract(identity *BFIBEIdentity) (*BFIBEPartialKey, error) {
+	secretKey, err := bfExtract(server.share, server.dst, identity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract partial key: %v", err)
+	}
+	return &BFIBEPartialKey{
+		Index: server.index,
+		Key:   secretKey.sk,
+	}, nil
+}
+
+// VerifyBFIBEPartialKey 使用第Index个服务器的公开分片验证部分私钥: e(g^{x_i}, H(Id)) =?= e(g, d_i)。
+// 下标超出范围或部分私钥不是有效的G2元素时返回错误。
+func VerifyBFIBEPartialKey(thresholdParams *BFIBEThresholdParams, identity *BFIBEIdentity, partialKey *BFIBEPartialKey) (bool, error) {
+	if partialKey.Index < 1 || partialKey.Index > len(thresholdParams.PublicShares) {
+		return false, fmt.Errorf("failed to verify partial key: index %d out of range [1, %d]", partialKey.Index, len(thresholdParams.PublicShares))
+	}
+	if partialKey.Key.IsInfinity() || !partialKey.Key.IsOnCurve() || !partialKey.Key.IsInSubGroup() {
+		return false, fmt.Errorf("failed to verify partial key: key is not a valid G2 element")
+	}
+	qid, err := bn254.HashToG2([]byte(identity.Id), thresholdParams.PublicParams.DST)
+	if err != nil {
+		return false, fmt.Errorf("failed to verify partial key: %v", err)
+	}
+	var negG bn254.G1Affine
+	negG.Neg(&thresholdParams.PublicParams.G1)
+	isValid, err := bn254.PairingCheck(
+		[]bn254.G1Affine{thresholdParams.PublicShares[partialKey.Index-1], negG},
+		[]bn254.G2Affine{qid, partialKey.Key},
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to verify partial key: %v", err)
+	}
+	return isValid, nil
+}
+
+// CombineBFIBEPartialKeys 验证部分私钥并将其中t个有效的部分私钥合并为普通私钥:
+// sk = sum(d_i^{Delta_{i,S}(0)}) = H(Id)^x
+// 为nil或无效的部分私钥与重复的下标被跳过,有效的部分私钥不足t个时返回错误。
+// 合并后检查e(g^x, H(Id)) = e(g, sk),公开分片与G1x不一致时返回错误。
+//
+// 参数:
+//   - thresholdParams: 门限公共参数
+//   - identity: 用户的身份标识符
+//   - partialKeys: 各服务器返回的部分私钥
+//
+// 返回值:
+//   - *BFIBESecretKey: 合并后的私钥,与单个PKG提取的私钥相同
+//   - error: 如果有效的部分私钥不足或合并失败,返回错误信息
+func CombineBFIBEPartialKeys(thresholdParams *BFIBEThresholdParams, identity *BFIBEIdentity, partialKeys []*BFIBEPartialKey) (*BFIBESecretKey, error) {
+	threshold := thresholdParams.Threshold
+	if threshold < 1 || threshold > len(thresholdParams.PublicShares) {
+		return nil, fmt.Errorf("failed to combine partial keys: invalid threshold %d of %d", threshold, len(thresholdParams.PublicShares))
+	}
+
+	// 选出前t个下标互不相同的有效部分私钥
+	var s []int
+	selected := make(map[int]bn254.G2Affine, threshold)
+	for _, partialKey := range partialKeys {
+		if len(s) == threshold {
+			break
+		}
+		if partialKey == nil {
+			continue
+		}
+		if _, ok := selected[partialKey.Index]; ok {
+			continue
+		}
+		isValid, err := VerifyBFIBEPartialKey(thresholdParams, identity, partialKey)
+		if err != nil || !isValid {
+			continue
+		}
+		selected[partialKey.Index] = partialKey.Key
+		s = append(s, partialKey.Index)
+	}
+	if len(s) < threshold {
+		return nil, fmt.Errorf("failed to combine partial keys: need %d valid partial keys, got %d", threshold, len(s))
+	}
+
+	var combined bn254.G2Jac
+	for _, i := range s {
+		delta := utils.ComputeLagrangeBasisMod(i, s, 0, ecc.BN254.ScalarField())
+		if delta == nil {
+			return nil, fmt.Errorf("failed to combine partial keys: failed to compute lagrange basis")
+		}
+		di := selected[i]
+		var term bn254.G2Jac
+		term.FromAffine(&di)
+		// d_i^{Delta_{i,S}(0)}
+		term.ScalarMultiplication(&term, delta)
+		combined.AddAssign(&term)
+	}
+	var sk bn254.G2Affine
+	sk.FromJacobian(&combined)
+
+	// e(g^x, qid) =?= e(g, sk)
+	qid, err := bn254.HashToG2([]byte(identity.Id), thresholdParams.PublicParams.DST)
+	if err != nil {
+		return nil, fmt.Errorf("failed to combine partial keys: %v", err)
+	}
+	var negG bn254.G1Affine
+	negG.Neg(&thresholdParams.PublicParams.G1)
+	isValid, err := bn254.PairingCheck(
+		[]bn254.G1Affine{thresholdParams.PublicParams.G1x, negG},
+		[]bn254.G2Affine{qid, sk},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to combine partial keys: %v", err)
+	}
+	if !isValid {
+		return nil, fmt.Errorf("failed to combine partial keys: public shares do not match the public params")
+	}
+	return &BFIBESecretKey{
+		sk: sk,
+	}, nil
+}
+
+// bfSplit 使用Shamir秘密共享将主密钥x拆分为n个分片x_i = f(i), i = 1, ..., n,f(0) = x。
+func bfSplit(x *big.Int, dst []byte, threshold int, n int) (*BFIBEThresholdParams, []*BFIBEKeyServer, error) {
+	if threshold < 1 || threshold > n {
+		return nil, nil, fmt.Errorf("failed to split master key: invalid threshold %d of %d", threshold, n)
+	}
+	// 与NewBFIBEKeyServer一致,主密钥为0时公开分片都是无穷远点
+	if x.Sign() == 0 {
+		return nil, nil, fmt.Errorf("failed to split master key: master key is zero")
+	}
+	// f(z) = x + a_1*z + ... + a_{t-1}*z^{t-1}
+	r := ecc.BN254.ScalarField()
+	polynomial := utils.GenerateRandomPolynomialMod(threshold, x, r)
+	servers := make([]*BFIBEKeyServer, n)
+	publicShares := make([]bn254.G1Affine, n)
+	for i := 1; i <= n; i++ {
+		servers[i-1] = &BFIBEKeyServer{
+			index: i,
+			share: utils.ComputePolynomialValueMod(polynomial, big.NewInt(int64(i)), r),
+			dst:   append([]byte{}, dst...),
+		}
+		publicShares[i-1] = servers[i-1].PublicShare()
+	}
+	return &BFIBEThresholdParams{
+		PublicParams: *bfSetUp(x, dst),
+		Threshold:    threshold,
+		PublicShares: publicShares,
+	}, servers, nil
+}
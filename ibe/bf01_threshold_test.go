@@ -0,0 +1,255 @@
+package ibe
+
+import (
+	"bytes"
+	"github.com/mmsyan/GnarkPairingProject/dkg"
+	"math/big"
+	"testing"
+)
+
+// TestBFIBEThreshold 测试(3, 5)门限PKG: 任意3个服务器的部分私钥合并后与单个PKG提取的私钥相同,可以解密。
+func TestBFIBEThreshold(t *testing.T) {
+	pkg, err := NewBFIBEPKG()
+	if err != nil {
+		t.Fatal("创建PKG失败:", err)
+	}
+	identity := &BFIBEIdentity{Id: "ChenBerry"}
+	expectedKey, err := pkg.Extract(identity)
+	if err != nil {
+		t.Fatal("密钥提取失败:", err)
+	}
+	thresholdParams, servers, err := pkg.Split(3, 5)
+	if err != nil {
+		t.Fatal("拆分主密钥失败:", err)
+	}
+	publicParams, err := pkg.Setup()
+	if err != nil {
+		t.Fatal("系统初始化失败:", err)
+	}
+	if !thresholdParams.PublicParams.G1x.Equal(&publicParams.G1x) {
+		t.Fatal("拆分后的公共参数与PKG不一致")
+	}
+
+	partialKeys := make([]*BFIBEPartialKey, len(servers))
+	for i, server := range servers {
+		partialKeys[i], err = server.Extract(identity)
+		if err != nil {
+			t.Fatal("部分私钥提取失败:", err)
+		}
+		isValid, err := VerifyBFIBEPartialKey(thresholdParams, identity, partialKeys[i])
+		if err != nil || !isValid {
+			t.Fatalf("服务器%d的部分私钥验证失败: %v", server.Index(), err)
+		}
+	}
+
+	sender, err := NewBFIBESender(&thresholdParams.PublicParams)
+	if err != nil {
+		t.Fatal("创建发送者失败:", err)
+	}
+	message := &BFIBEMessage{Message: []byte("Hello World")}
+	ciphertext, err := sender.EncryptHybrid(identity, message)
+	if err != nil {
+		t.Fatal("加密失败:", err)
+	}
+
+	for _, subset := range [][]int{{0, 1, 2}, {4, 2, 0}, {1, 3, 4}} {
+		var selected []*BFIBEPartialKey
+		for _, i := range subset {
+			selected = append(selected, partialKeys[i])
+		}
+		secretKey, err := CombineBFIBEPartialKeys(thresholdParams, identity, selected)
+		if err != nil {
+			t.Fatal("合并部分私钥失败:", err)
+		}
+		if !secretKey.sk.Equal(&expectedKey.sk) {
+			t.Fatalf("服务器%v合并的私钥与PKG提取的私钥不一致", subset)
+		}
+		recipient, err := NewBFIBERecipient(secretKey)
+		if err != nil {
+			t.Fatal("创建接收者失败:", err)
+		}
+		decryptedMessage, err := recipient.DecryptHybrid(ciphertext)
+		if err != nil || !bytes.Equal(decryptedMessage.Message, message.Message) {
+			t.Fatal("解密错误:", err)
+		}
+	}
+
+	// 少于t个部分私钥
+	if _, err := CombineBFIBEPartialKeys(thresholdParams, identity, partialKeys[:2]); err == nil {
+		t.Fatal("少于门限数量的部分私钥合并应当失败")
+	}
+	// nil与重复的部分私钥不计入门限
+	if _, err := CombineBFIBEPartialKeys(thresholdParams, identity, []*BFIBEPartialKey{nil, partialKeys[0], nil, partialKeys[1]}); err == nil {
+		t.Fatal("有效部分私钥不足时合并应当失败")
+	}
+	if _, err := CombineBFIBEPartialKeys(thresholdParams, identity, []*BFIBEPartialKey{nil, partialKeys[0], nil, partialKeys[1], partialKeys[2]}); err != nil {
+		t.Fatal("nil部分私钥应当被跳过:", err)
+	}
+	if _, err := CombineBFIBEPartialKeys(thresholdParams, identity, []*BFIBEPartialKey{partialKeys[0], partialKeys[0], partialKeys[1]}); err == nil {
+		t.Fatal("重复的部分私钥合并应当失败")
+	}
+}
+
+// TestBFIBEThresholdMaliciousServer 测试错误的部分私钥被验证拒绝,并在合并时被跳过。
+func TestBFIBEThresholdMaliciousServer(t *testing.T) {
+	instance, err := NewBFIBEInstance()
+	if err != nil {
+		t.Fatal("创建IBE实例失败:", err)
+	}
+	identity := &BFIBEIdentity{Id: "ChenBerry"}
+	expectedKey, err := instance.KeyGenerate(identity)
+	if err != nil {
+		t.Fatal("密钥生成失败:", err)
+	}
+	thresholdParams, servers, err := instance.Split(2, 4)
+	if err != nil {
+		t.Fatal("拆分主密钥失败:", err)
+	}
+
+	// 服务器1返回其他身份的部分私钥,服务器2冒用服务器3的下标
+	wrongIdentity, err := servers[0].Extract(&BFIBEIdentity{Id: "Alice"})
+	if err != nil {
+		t.Fatal("部分私钥提取失败:", err)
+	}
+	if isValid, err := VerifyBFIBEPartialKey(thresholdParams, identity, wrongIdentity); err != nil || isValid {
+		t.Fatal("其他身份的部分私钥应当验证失败")
+	}
+	wrongIndex, err := servers[1].Extract(identity)
+	if err != nil {
+		t.Fatal("部分私钥提取失败:", err)
+	}
+	wrongIndex.Index = 3
+	if isValid, err := VerifyBFIBEPartialKey(thresholdParams, identity, wrongIndex); err != nil || isValid {
+		t.Fatal("下标错误的部分私钥应当验证失败")
+	}
+	if _, err := VerifyBFIBEPartialKey(thresholdParams, identity, &BFIBEPartialKey{Index: 5, Key: wrongIndex.Key}); err == nil {
+		t.Fatal("下标超出范围的部分私钥应当返回错误")
+	}
+	if _, err := VerifyBFIBEPartialKey(thresholdParams, identity, &BFIBEPartialKey{Index: 1}); err == nil {
+		t.Fatal("无穷远点部分私钥应当返回错误")
+	}
+
+	var partialKeys []*BFIBEPartialKey
+	partialKeys = append(partialKeys, wrongIdentity, wrongIndex)
+	for _, server := range servers[2:] {
+		partialKey, err := server.Extract(identity)
+		if err != nil {
+			t.Fatal("部分私钥提取失败:", err)
+		}
+		partialKeys = append(partialKeys, partialKey)
+	}
+	secretKey, err := CombineBFIBEPartialKeys(thresholdParams, identity, partialKeys)
+	if err != nil {
+		t.Fatal("合并部分私钥失败:", err)
+	}
+	if !secretKey.sk.Equal(&expectedKey.sk) {
+		t.Fatal("合并的私钥与实例生成的私钥不一致")
+	}
+
+	// 公开分片与G1x不一致
+	otherParams, _, err := instance.Split(2, 4)
+	if err != nil {
+		t.Fatal("拆分主密钥失败:", err)
+	}
+	mismatched := *otherParams
+	mismatched.PublicShares = thresholdParams.PublicShares
+	other, err := NewBFIBEInstance()
+	if err != nil {
+		t.Fatal("创建IBE实例失败:", err)
+	}
+	otherPublicParams, err := other.SetUp()
+	if err != nil {
+		t.Fatal("系统初始化失败:", err)
+	}
+	mismatched.PublicParams = *otherPublicParams
+	if _, err := CombineBFIBEPartialKeys(&mismatched, identity, partialKeys); err == nil {
+		t.Fatal("公开分片与公共参数不一致时合并应当失败")
+	}
+
+	if _, _, err := instance.Split(5, 4); err == nil {
+		t.Fatal("门限大于服务器数量时拆分应当失败")
+	}
+}
+
+// TestBFIBEKeyServerZeroShare 测试为0的主密钥分片被拒绝: 其公开分片为无穷远点。
+func TestBFIBEKeyServerZeroShare(t *testing.T) {
+	if _, err := NewBFIBEKeyServer(1, big.NewInt(0), []byte(BFIBEDefaultDST)); err == nil {
+		t.Fatal("为0的分片应当被拒绝")
+	}
+	if _, err := NewBFIBEKeyServer(1, big.NewInt(1), []byte(BFIBEDefaultDST)); err != nil {
+		t.Fatal("创建密钥服务器失败:", err)
+	}
+	if _, _, err := bfSplit(big.NewInt(0), []byte(BFIBEDefaultDST), 1, 3); err == nil {
+		t.Fatal("主密钥为0时拆分应当失败")
+	}
+}
+
+// TestBFIBEThresholdDKG 测试DKG的输出作为BF01门限密钥生成中心的主密钥分片。
+func TestBFIBEThresholdDKG(t *testing.T) {
+	const n, threshold = 4, 3
+	params, err := dkg.NewParams(n, threshold)
+	if err != nil {
+		t.Fatal("创建DKG参数失败:", err)
+	}
+	transport := dkg.NewInMemoryTransport(n)
+	participants := make([]*dkg.Participant, n)
+	for i := 1; i <= n; i++ {
+		participants[i-1], err = dkg.NewParticipant(params, i, transport)
+		if err != nil {
+			t.Fatal("创建DKG参与方失败:", err)
+		}
+	}
+	keyShares, err := dkg.Run(participants)
+	if err != nil {
+		t.Fatal("DKG失败:", err)
+	}
+
+	dst := []byte(BFIBEDefaultDST)
+	thresholdParams, err := NewBFIBEThresholdParamsFromDKG(keyShares[0], dst)
+	if err != nil {
+		t.Fatal("创建门限公共参数失败:", err)
+	}
+
+	identity := &BFIBEIdentity{Id: "ChenBerry"}
+	var partialKeys []*BFIBEPartialKey
+	for _, keyShare := range keyShares[1:] {
+		server, err := NewBFIBEKeyServerFromDKG(keyShare, dst)
+		if err != nil {
+			t.Fatal("创建密钥服务器失败:", err)
+		}
+		partialKey, err := server.Extract(identity)
+		if err != nil {
+			t.Fatal("部分私钥提取失败:", err)
+		}
+		partialKeys = append(partialKeys, partialKey)
+	}
+	secretKey, err := CombineBFIBEPartialKeys(thresholdParams, identity, partialKeys)
+	if err != nil {
+		t.Fatal("合并部分私钥失败:", err)
+	}
+
+	sender, err := NewBFIBESender(&thresholdParams.PublicParams)
+	if err != nil {
+		t.Fatal("创建发送者失败:", err)
+	}
+	message := &BFIBEMessage{Message: []byte("encrypted to a dkg key")}
+	ciphertext, err := sender.EncryptFullIdent(identity, message)
+	if err != nil {
+		t.Fatal("加密失败:", err)
+	}
+	recipient, err := NewBFIBERecipient(secretKey)
+	if err != nil {
+		t.Fatal("创建接收者失败:", err)
+	}
+	decryptedMessage, err := recipient.DecryptFullIdent(ciphertext)
+	if err != nil || !bytes.Equal(decryptedMessage.Message, message.Message) {
+		t.Fatal("解密错误:", err)
+	}
+
+	if _, err := NewBFIBEKeyServerFromDKG(nil, dst); err == nil {
+		t.Fatal("nil分片应当被拒绝")
+	}
+	if _, err := NewBFIBEThresholdParamsFromDKG(nil, dst); err == nil {
+		t.Fatal("nil分片应当被拒绝")
+	}
+}
@@ -0,0 +1,564 @@
+package ibe
+
+// 编码格式
+//
+// 公共参数、私钥与密文编码为带版本的二进制格式:
+//   版本(1字节) || 方案标识(1字节) || 曲线标识(2字节大端) || 对象类型(1字节) || 内容
+//
+// 曲线标识是本格式自己定义的CurveID,固定BN254 = 1,不直接使用gnark-crypto的ecc.ID
+// (ecc.ID是iota枚举,取值可能随gnark-crypto版本变化)。
+//
+// 内容中的G1、G2上的点使用gnark-crypto的压缩编码(Bytes(),分别为32与64字节),GT元素为384字节,
+// Zp上的标量为32字节大端整数,变长字段使用4字节大端长度前缀,位于末尾的变长字段直接占用剩余的字节:
+//   - BF01公共参数: G1 || G1x || DST长度 || DST
+//   - BF01私钥: sk
+//   - BF01密文: C1 || C2
+//   - BF01混合加密密文: C1 || C2
+//   - BF01 FullIdent密文: U || V(32字节) || W
+//   - BB04公共参数: g1 || g2 || x || y
+//   - BB04私钥: r || k
+//   - BB04密文: a || b || c
+//
+// 解码是严格的: 版本、方案、曲线与对象类型必须一致,长度必须精确匹配,
+// 点必须是规范的压缩编码、在曲线上、位于素数阶子群中且不是无穷远点,GT元素必须位于配对的目标子群中。
+//
+// EncodePEM/DecodePEM为二进制编码加上PEM外壳,PEM块的类型(如"BF01 IBE SECRET KEY")由编码头部决定。
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"math/big"
+)
+
+// EncodingVersion 当前的编码版本。
+const EncodingVersion = 1
+
+// SchemeID 标识编码所属的IBE方案。
+type SchemeID byte
+
+const (
+	SchemeBF01 SchemeID = 1
+	SchemeBB04 SchemeID = 2
+)
+
+func (scheme SchemeID) String() string {
+	switch scheme {
+	case SchemeBF01:
+		return "BF01"
+	case SchemeBB04:
+		return "BB04"
+	default:
+		return fmt.Sprintf("SchemeID(%d)", byte(scheme))
+	}
+}
+
+// CurveID 标识编码所使用的曲线,取值是编码格式的一部分,一经分配不再改变。
+type CurveID uint16
+
+const (
+	CurveBN254 CurveID = 1
+)
+
+func (curve CurveID) String() string {
+	switch curve {
+	case CurveBN254:
+		return "BN254"
+	default:
+		return fmt.Sprintf("CurveID(%d)", uint16(curve))
+	}
+}
+
+// ECC 返回曲线标识对应的ecc.ID,未定义的曲线标识返回ErrUnexpectedType。
+func (curve CurveID) ECC() (ecc.ID, error) {
+	switch curve {
+	case CurveBN254:
+		return ecc.BN254, nil
+	default:
+		return ecc.UNKNOWN, ErrUnexpectedType
+	}
+}
+
+// CurveIDFromECC 返回ecc.ID对应的曲线标识,没有分配标识的曲线返回ErrUnexpectedType。
+func CurveIDFromECC(id ecc.ID) (CurveID, error) {
+	switch id {
+	case ecc.BN254:
+		return CurveBN254, nil
+	default:
+		return 0, ErrUnexpectedType
+	}
+}
+
+// objectType 标识编码的对象。
+type objectType byte
+
+const (
+	objectPublicParams objectType = iota + 1
+	objectSecretKey
+	objectCiphertext
+	objectHybridCiphertext
+	objectFullIdentCiphertext
+)
+
+func (object objectType) String() string {
+	switch object {
+	case objectPublicParams:
+		return "PUBLIC PARAMS"
+	case objectSecretKey:
+		return "SECRET KEY"
+	case objectCiphertext:
+		return "CIPHERTEXT"
+	case objectHybridCiphertext:
+		return "HYBRID CIPHERTEXT"
+	case objectFullIdentCiphertext:
+		return "FULLIDENT CIPHERTEXT"
+	default:
+		return fmt.Sprintf("OBJECT %d", byte(object))
+	}
+}
+
+const (
+	encodingHeaderSize = 5
+	scalarSize         = 32
+)
+
+// 解码错误的类别,可以通过errors.Is判断。
+var (
+	ErrInvalidLength      = errors.New("invalid encoding length")
+	ErrInvalidEncoding    = errors.New("invalid encoding")
+	ErrUnsupportedVersion = errors.New("unsupported encoding version")
+	ErrUnexpectedType     = errors.New("unexpected scheme, curve or object type")
+	ErrPointAtInfinity    = errors.New("point at infinity")
+	ErrNotOnCurve         = errors.New("point is not on the curve")
+	ErrNotInSubgroup      = errors.New("element is not in the prime-order subgroup")
+	ErrInvalidScalar      = errors.New("scalar is out of range")
+)
+
+// DecodeError 表示解码公共参数、私钥或密文失败,Object为被解码对象的名称,Err为上面定义的错误类别之一。
+type DecodeError struct {
+	Object string
+	Err    error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("failed to decode %s: %v", e.Object, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// MarshalBFIBEPublicParams 编码BF01公共参数。
+func MarshalBFIBEPublicParams(publicParams *BFIBEPublicParams) []byte {
+	data := appendHeader(SchemeBF01, objectPublicParams)
+	data = appendG1(data, publicParams.G1)
+	data = appendG1(data, publicParams.G1x)
+	data = binary.BigEndian.AppendUint32(data, uint32(len(publicParams.DST)))
+	return append(data, publicParams.DST...)
+}
+
+// UnmarshalBFIBEPublicParams 解码BF01公共参数,DST不能为空。
+func UnmarshalBFIBEPublicParams(data []byte) (*BFIBEPublicParams, error) {
+	const object = "BF01 public params"
+	decoder, err := newDecoder(object, data, SchemeBF01, objectPublicParams)
+	if err != nil {
+		return nil, err
+	}
+	publicParams := &BFIBEPublicParams{
+		G1:  decoder.g1(),
+		G1x: decoder.g1(),
+		DST: decoder.lengthPrefixed(),
+	}
+	if err := decoder.finish(); err != nil {
+		return nil, err
+	}
+	if len(publicParams.DST) == 0 {
+		return nil, &DecodeError{Object: object, Err: ErrInvalidEncoding}
+	}
+	return publicParams, nil
+}
+
+// MarshalBFIBESecretKey 编码BF01私钥。
+func MarshalBFIBESecretKey(secretKey *BFIBESecretKey) []byte {
+	return appendG2(appendHeader(SchemeBF01, objectSecretKey), secretKey.sk)
+}
+
+// UnmarshalBFIBESecretKey 解码BF01私钥。
+func UnmarshalBFIBESecretKey(data []byte) (*BFIBESecretKey, error) {
+	decoder, err := newDecoder("BF01 secret key", data, SchemeBF01, objectSecretKey)
+	if err != nil {
+		return nil, err
+	}
+	secretKey := &BFIBESecretKey{sk: decoder.g2()}
+	if err := decoder.finish(); err != nil {
+		return nil, err
+	}
+	return secretKey, nil
+}
+
+// MarshalBFIBECiphertext 编码BF01密文。
+func MarshalBFIBECiphertext(ciphertext *BFIBECiphertext) []byte {
+	data := appendG1(appendHeader(SchemeBF01, objectCiphertext), ciphertext.C1)
+	return append(data, ciphertext.C2...)
+}
+
+// UnmarshalBFIBECiphertext 解码BF01密文。
+func UnmarshalBFIBECiphertext(data []byte) (*BFIBECiphertext, error) {
+	decoder, err := newDecoder("BF01 ciphertext", data, SchemeBF01, objectCiphertext)
+	if err != nil {
+		return nil, err
+	}
+	ciphertext := &BFIBECiphertext{
+		C1: decoder.g1(),
+		C2: decoder.rest(),
+	}
+	if err := decoder.finish(); err != nil {
+		return nil, err
+	}
+	return ciphertext, nil
+}
+
+// MarshalBFIBEHybridCiphertext 编码BF01混合加密密文。
+func MarshalBFIBEHybridCiphertext(ciphertext *BFIBEHybridCiphertext) []byte {
+	data := appendG1(appendHeader(SchemeBF01, objectHybridCiphertext), ciphertext.C1)
+	return append(data, ciphertext.C2...)
+}
+
+// UnmarshalBFIBEHybridCiphertext 解码BF01混合加密密文。
+func UnmarshalBFIBEHybridCiphertext(data []byte) (*BFIBEHybridCiphertext, error) {
+	decoder, err := newDecoder("BF01 hybrid ciphertext", data, SchemeBF01, objectHybridCiphertext)
+	if err != nil {
+		return nil, err
+	}
+	ciphertext := &BFIBEHybridCiphertext{
+		C1: decoder.g1(),
+		C2: decoder.rest(),
+	}
+	if err := decoder.finish(); err != nil {
+		return nil, err
+	}
+	return ciphertext, nil
+}
+
+// MarshalBFIBEFullIdentCiphertext 编码BF01 FullIdent密文,V必须为BFIBESigmaSize字节。
+func MarshalBFIBEFullIdentCiphertext(ciphertext *BFIBEFullIdentCiphertext) ([]byte, error) {
+	if len(ciphertext.V) != BFIBESigmaSize {
+		return nil, fmt.Errorf("failed to encode FullIdent ciphertext: V must be %d bytes, got %d", BFIBESigmaSize, len(ciphertext.V))
+	}
+	data := appendG1(appendHeader(SchemeBF01, objectFullIdentCiphertext), ciphertext.U)
+	data = append(data, ciphertext.V...)
+	return append(data, ciphertext.W...), nil
+}
+
+// UnmarshalBFIBEFullIdentCiphertext 解码BF01 FullIdent密文。
+func UnmarshalBFIBEFullIdentCiphertext(data []byte) (*BFIBEFullIdentCiphertext, error) {
+	decoder, err := newDecoder("BF01 FullIdent ciphertext", data, SchemeBF01, objectFullIdentCiphertext)
+	if err != nil {
+		return nil, err
+	}
+	ciphertext := &BFIBEFullIdentCiphertext{
+		U: decoder.g1(),
+		V: decoder.bytes(BFIBESigmaSize),
+		W: decoder.rest(),
+	}
+	if err := decoder.finish(); err != nil {
+		return nil, err
+	}
+	return ciphertext, nil
+}
+
+// MarshalBBIBEPublicParams 编码BB04公共参数。
+func MarshalBBIBEPublicParams(publicParams *BBIBEPublicParams) []byte {
+	data := appendHeader(SchemeBB04, objectPublicParams)
+	data = appendG1(data, publicParams.g1)
+	data = appendG2(data, publicParams.g2)
+	data = appendG1(data, publicParams.x)
+	return appendG1(data, publicParams.y)
+}
+
+// UnmarshalBBIBEPublicParams 解码BB04公共参数。
+func UnmarshalBBIBEPublicParams(data []byte) (*BBIBEPublicParams, error) {
+	decoder, err := newDecoder("BB04 public params", data, SchemeBB04, objectPublicParams)
+	if err != nil {
+		return nil, err
+	}
+	publicParams := &BBIBEPublicParams{
+		g1: decoder.g1(),
+		g2: decoder.g2(),
+		x:  decoder.g1(),
+		y:  decoder.g1(),
+	}
+	if err := decoder.finish(); err != nil {
+		return nil, err
+	}
+	return publicParams, nil
+}
+
+// MarshalBBIBESecretKey 编码BB04私钥,r必须位于[0, q)中。
+func MarshalBBIBESecretKey(secretKey *BBIBESecretKey) ([]byte, error) {
+	if secretKey.r == nil || secretKey.r.Sign() < 0 || secretKey.r.Cmp(ecc.BN254.ScalarField()) >= 0 {
+		return nil, fmt.Errorf("failed to encode BB04 secret key: %w", ErrInvalidScalar)
+	}
+	data := appendHeader(SchemeBB04, objectSecretKey)
+	data = append(data, secretKey.r.FillBytes(make([]byte, scalarSize))...)
+	return appendG2(data, secretKey.k), nil
+}
+
+// UnmarshalBBIBESecretKey 解码BB04私钥,拒绝大于等于q的r。
+func UnmarshalBBIBESecretKey(data []byte) (*BBIBESecretKey, error) {
+	decoder, err := newDecoder("BB04 secret key", data, SchemeBB04, objectSecretKey)
+	if err != nil {
+		return nil, err
+	}
+	secretKey := &BBIBESecretKey{
+		r: decoder.scalar(),
+		k: decoder.g2(),
+	}
+	if err := decoder.finish(); err != nil {
+		return nil, err
+	}
+	return secretKey, nil
+}
+
+// MarshalBBIBECiphertext 编码BB04密文。
+func MarshalBBIBECiphertext(ciphertext *BBIBECiphertext) []byte {
+	data := appendHeader(SchemeBB04, objectCiphertext)
+	data = appendG1(data, ciphertext.a)
+	data = appendG1(data, ciphertext.b)
+	c := ciphertext.c.Bytes()
+	return append(data, c[:]...)
+}
+
+// UnmarshalBBIBECiphertext 解码BB04密文。
+func UnmarshalBBIBECiphertext(data []byte) (*BBIBECiphertext, error) {
+	decoder, err := newDecoder("BB04 ciphertext", data, SchemeBB04, objectCiphertext)
+	if err != nil {
+		return nil, err
+	}
+	ciphertext := &BBIBECiphertext{
+		a: decoder.g1(),
+		b: decoder.g1(),
+		c: decoder.gt(),
+	}
+	if err := decoder.finish(); err != nil {
+		return nil, err
+	}
+	return ciphertext, nil
+}
+
+// EncodePEM 为Marshal*函数的输出加上PEM外壳,块类型由编码头部中的方案与对象类型决定。
+func EncodePEM(data []byte) ([]byte, error) {
+	blockType, err := pemBlockType(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode PEM: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: data}), nil
+}
+
+// DecodePEM 解析恰好一个PEM块并返回其中的二进制编码,之后可以交给对应的Unmarshal*函数解码。
+// 块类型必须与编码头部一致,不允许PEM头部字段,块之后只允许空白字符。
+func DecodePEM(data []byte) ([]byte, error) {
+	block, rest := pem.Decode(data)
+	if block == nil {
+		return nil, &DecodeError{Object: "PEM block", Err: ErrInvalidEncoding}
+	}
+	if len(block.Headers) != 0 || len(bytes.TrimSpace(rest)) != 0 {
+		return nil, &DecodeError{Object: "PEM block", Err: ErrInvalidEncoding}
+	}
+	blockType, err := pemBlockType(block.Bytes)
+	if err != nil {
+		return nil, &DecodeError{Object: "PEM block", Err: err}
+	}
+	if blockType != block.Type {
+		return nil, &DecodeError{Object: "PEM block", Err: ErrUnexpectedType}
+	}
+	return block.Bytes, nil
+}
+
+// pemBlockType 根据编码头部返回PEM块类型,例如"BF01 IBE PUBLIC PARAMS"。
+func pemBlockType(data []byte) (string, error) {
+	if len(data) < encodingHeaderSize {
+		return "", ErrInvalidLength
+	}
+	if data[0] != EncodingVersion {
+		return "", ErrUnsupportedVersion
+	}
+	scheme, object := SchemeID(data[1]), objectType(data[4])
+	if !validObject(scheme, object) || CurveID(binary.BigEndian.Uint16(data[2:4])) != CurveBN254 {
+		return "", ErrUnexpectedType
+	}
+	return fmt.Sprintf("%v IBE %v", scheme, object), nil
+}
+
+// validObject 返回方案是否定义了该对象类型的编码。
+func validObject(scheme SchemeID, object objectType) bool {
+	switch scheme {
+	case SchemeBF01:
+		return object >= objectPublicParams && object <= objectFullIdentCiphertext
+	case SchemeBB04:
+		return object >= objectPublicParams && object <= objectCiphertext
+	default:
+		return false
+	}
+}
+
+func appendHeader(scheme SchemeID, object objectType) []byte {
+	data := []byte{EncodingVersion, byte(scheme)}
+	data = binary.BigEndian.AppendUint16(data, uint16(CurveBN254))
+	return append(data, byte(object))
+}
+
+func appendG1(data []byte, point bn254.G1Affine) []byte {
+	encoded := point.Bytes()
+	return append(data, encoded[:]...)
+}
+
+func appendG2(data []byte, point bn254.G2Affine) []byte {
+	encoded := point.Bytes()
+	return append(data, encoded[:]...)
+}
+
+// decoder 按顺序读取编码内容,记录遇到的第一个错误,之后的读取返回零值,由finish统一返回错误。
+type decoder struct {
+	object string
+	data   []byte
+	err    error
+}
+
+// newDecoder 检查编码头部并返回读取内容的decoder。
+func newDecoder(object string, data []byte, scheme SchemeID, expected objectType) (*decoder, error) {
+	if len(data) < encodingHeaderSize {
+		return nil, &DecodeError{Object: object, Err: ErrInvalidLength}
+	}
+	if data[0] != EncodingVersion {
+		return nil, &DecodeError{Object: object, Err: ErrUnsupportedVersion}
+	}
+	if SchemeID(data[1]) != scheme || CurveID(binary.BigEndian.Uint16(data[2:4])) != CurveBN254 || objectType(data[4]) != expected {
+		return nil, &DecodeError{Object: object, Err: ErrUnexpectedType}
+	}
+	return &decoder{object: object, data: data[encodingHeaderSize:]}, nil
+}
+
+// finish 返回读取过程中的错误,并检查没有多余的字节。
+func (d *decoder) finish() error {
+	if d.err == nil && len(d.data) != 0 {
+		d.err = ErrInvalidLength
+	}
+	if d.err != nil {
+		return &DecodeError{Object: d.object, Err: d.err}
+	}
+	return nil
+}
+
+// bytes 读取固定长度的字段。
+func (d *decoder) bytes(length int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if len(d.data) < length {
+		d.err = ErrInvalidLength
+		return nil
+	}
+	field := append([]byte{}, d.data[:length]...)
+	d.data = d.data[length:]
+	return field
+}
+
+// lengthPrefixed 读取4字节大端长度前缀的字段。
+func (d *decoder) lengthPrefixed() []byte {
+	length := d.bytes(4)
+	if d.err != nil {
+		return nil
+	}
+	if uint64(binary.BigEndian.Uint32(length)) > uint64(len(d.data)) {
+		d.err = ErrInvalidLength
+		return nil
+	}
+	return d.bytes(int(binary.BigEndian.Uint32(length)))
+}
+
+// rest 读取剩余的全部字节。
+func (d *decoder) rest() []byte {
+	return d.bytes(len(d.data))
+}
+
+// scalar 读取32字节大端整数,必须小于q。
+func (d *decoder) scalar() *big.Int {
+	encoded := d.bytes(scalarSize)
+	if d.err != nil {
+		return nil
+	}
+	scalar := new(big.Int).SetBytes(encoded)
+	if scalar.Cmp(ecc.BN254.ScalarField()) >= 0 {
+		d.err = ErrInvalidScalar
+		return nil
+	}
+	return scalar
+}
+
+// g1 读取压缩的G1点,检查点在曲线上、位于子群中且不是无穷远点。
+func (d *decoder) g1() bn254.G1Affine {
+	var point bn254.G1Affine
+	encoded := d.bytes(bn254.SizeOfG1AffineCompressed)
+	if d.err != nil {
+		return point
+	}
+	// 压缩标记必须与长度一致: 未压缩的编码需要读取更多字节,解码失败
+	if err := bn254.NewDecoder(bytes.NewReader(encoded), bn254.NoSubgroupChecks()).Decode(&point); err != nil {
+		d.err = ErrInvalidEncoding
+		return point
+	}
+	switch {
+	case point.IsInfinity():
+		d.err = ErrPointAtInfinity
+	case !point.IsOnCurve():
+		d.err = ErrNotOnCurve
+	case !point.IsInSubGroup():
+		d.err = ErrNotInSubgroup
+	}
+	return point
+}
+
+// g2 读取压缩的G2点,检查点在曲线上、位于子群中且不是无穷远点。
+func (d *decoder) g2() bn254.G2Affine {
+	var point bn254.G2Affine
+	encoded := d.bytes(bn254.SizeOfG2AffineCompressed)
+	if d.err != nil {
+		return point
+	}
+	if err := bn254.NewDecoder(bytes.NewReader(encoded), bn254.NoSubgroupChecks()).Decode(&point); err != nil {
+		d.err = ErrInvalidEncoding
+		return point
+	}
+	switch {
+	case point.IsInfinity():
+		d.err = ErrPointAtInfinity
+	case !point.IsOnCurve():
+		d.err = ErrNotOnCurve
+	case !point.IsInSubGroup():
+		d.err = ErrNotInSubgroup
+	}
+	return point
+}
+
+// gt 读取384字节的GT元素,检查各分量是规范编码且元素位于配对的目标子群中。
+func (d *decoder) gt() bn254.GT {
+	var element bn254.GT
+	encoded := d.bytes(bn254.SizeOfGT)
+	if d.err != nil {
+		return element
+	}
+	if err := element.SetBytes(encoded); err != nil {
+		d.err = ErrInvalidEncoding
+		return element
+	}
+	// 0不是GT中的元素,但会通过IsInSubGroup的检查
+	if element.IsZero() || !element.IsInSubGroup() {
+		d.err = ErrNotInSubgroup
+	}
+	return element
+}
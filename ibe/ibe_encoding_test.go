@@ -0,0 +1,330 @@
+package ibe
+
+import (
+	"bytes"
+	"errors"
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"math/big"
+	"testing"
+)
+
+// TestBFIBEEncodingRoundTrip 测试BF01公共参数、私钥与三种密文经过二进制编码与PEM外壳后仍然可用。
+func TestBFIBEEncodingRoundTrip(t *testing.T) {
+	pkg, err := NewBFIBEPKG()
+	if err != nil {
+		t.Fatal("创建PKG失败:", err)
+	}
+	publicParams, err := pkg.Setup()
+	if err != nil {
+		t.Fatal("系统初始化失败:", err)
+	}
+	identity := &BFIBEIdentity{Id: "ChenBerry"}
+	secretKey, err := pkg.Extract(identity)
+	if err != nil {
+		t.Fatal("密钥提取失败:", err)
+	}
+
+	// 公共参数与私钥经过PEM传递
+	paramsPEM, err := EncodePEM(MarshalBFIBEPublicParams(publicParams))
+	if err != nil {
+		t.Fatal("编码公共参数失败:", err)
+	}
+	if !bytes.HasPrefix(paramsPEM, []byte("-----BEGIN BF01 IBE PUBLIC PARAMS-----")) {
+		t.Fatalf("unexpected PEM block: %s", paramsPEM)
+	}
+	paramsData, err := DecodePEM(paramsPEM)
+	if err != nil {
+		t.Fatal("解析PEM失败:", err)
+	}
+	decodedParams, err := UnmarshalBFIBEPublicParams(paramsData)
+	if err != nil {
+		t.Fatal("解码公共参数失败:", err)
+	}
+	sender, err := NewBFIBESender(decodedParams)
+	if err != nil {
+		t.Fatal("创建发送者失败:", err)
+	}
+	keyPEM, err := EncodePEM(MarshalBFIBESecretKey(secretKey))
+	if err != nil {
+		t.Fatal("编码私钥失败:", err)
+	}
+	keyData, err := DecodePEM(keyPEM)
+	if err != nil {
+		t.Fatal("解析PEM失败:", err)
+	}
+	decodedKey, err := UnmarshalBFIBESecretKey(keyData)
+	if err != nil {
+		t.Fatal("解码私钥失败:", err)
+	}
+	recipient, err := NewBFIBERecipient(decodedKey)
+	if err != nil {
+		t.Fatal("创建接收者失败:", err)
+	}
+
+	message := &BFIBEMessage{Message: []byte("Hello World")}
+
+	ciphertext, err := sender.Encrypt(identity, message)
+	if err != nil {
+		t.Fatal("加密失败:", err)
+	}
+	decodedCiphertext, err := UnmarshalBFIBECiphertext(MarshalBFIBECiphertext(ciphertext))
+	if err != nil {
+		t.Fatal("解码密文失败:", err)
+	}
+	decryptedMessage, err := recipient.Decrypt(decodedCiphertext)
+	if err != nil || !bytes.Equal(decryptedMessage.Message, message.Message) {
+		t.Fatal("BasicIdent解密错误:", err)
+	}
+
+	hybridCiphertext, err := sender.EncryptHybrid(identity, message)
+	if err != nil {
+		t.Fatal("加密失败:", err)
+	}
+	decodedHybrid, err := UnmarshalBFIBEHybridCiphertext(MarshalBFIBEHybridCiphertext(hybridCiphertext))
+	if err != nil {
+		t.Fatal("解码密文失败:", err)
+	}
+	decryptedMessage, err = recipient.DecryptHybrid(decodedHybrid)
+	if err != nil || !bytes.Equal(decryptedMessage.Message, message.Message) {
+		t.Fatal("混合加密解密错误:", err)
+	}
+
+	fullIdentCiphertext, err := sender.EncryptFullIdent(identity, message)
+	if err != nil {
+		t.Fatal("加密失败:", err)
+	}
+	encoded, err := MarshalBFIBEFullIdentCiphertext(fullIdentCiphertext)
+	if err != nil {
+		t.Fatal("编码密文失败:", err)
+	}
+	decodedFullIdent, err := UnmarshalBFIBEFullIdentCiphertext(encoded)
+	if err != nil {
+		t.Fatal("解码密文失败:", err)
+	}
+	decryptedMessage, err = recipient.DecryptFullIdent(decodedFullIdent)
+	if err != nil || !bytes.Equal(decryptedMessage.Message, message.Message) {
+		t.Fatal("FullIdent解密错误:", err)
+	}
+}
+
+// TestBBIBEEncodingRoundTrip 测试BB04公共参数、私钥与密文的编码。
+func TestBBIBEEncodingRoundTrip(t *testing.T) {
+	instance, err := NewBBIBEInstance()
+	if err != nil {
+		t.Fatal("创建IBE实例失败:", err)
+	}
+	publicParams, err := instance.SetUp()
+	if err != nil {
+		t.Fatal("系统初始化失败:", err)
+	}
+	identity := &BBIBEIdentity{Id: big.NewInt(123456)}
+	secretKey, err := instance.KeyGenerate(identity)
+	if err != nil {
+		t.Fatal("密钥生成失败:", err)
+	}
+
+	decodedParams, err := UnmarshalBBIBEPublicParams(MarshalBBIBEPublicParams(publicParams))
+	if err != nil {
+		t.Fatal("解码公共参数失败:", err)
+	}
+	keyData, err := MarshalBBIBESecretKey(secretKey)
+	if err != nil {
+		t.Fatal("编码私钥失败:", err)
+	}
+	keyPEM, err := EncodePEM(keyData)
+	if err != nil {
+		t.Fatal("编码私钥失败:", err)
+	}
+	if !bytes.HasPrefix(keyPEM, []byte("-----BEGIN BB04 IBE SECRET KEY-----")) {
+		t.Fatalf("unexpected PEM block: %s", keyPEM)
+	}
+	keyData, err = DecodePEM(keyPEM)
+	if err != nil {
+		t.Fatal("解析PEM失败:", err)
+	}
+	decodedKey, err := UnmarshalBBIBESecretKey(keyData)
+	if err != nil {
+		t.Fatal("解码私钥失败:", err)
+	}
+
+	// 严格解码要求密文中的c位于GT中,因此消息取GT中的元素
+	_, _, g1, g2 := bn254.Generators()
+	m, err := bn254.Pair([]bn254.G1Affine{*new(bn254.G1Affine).ScalarMultiplication(&g1, big.NewInt(42))}, []bn254.G2Affine{g2})
+	if err != nil {
+		t.Fatal("配对失败:", err)
+	}
+	ciphertext, err := instance.Encrypt(&BBIBEMessage{Message: m}, identity, decodedParams)
+	if err != nil {
+		t.Fatal("加密失败:", err)
+	}
+	decodedCiphertext, err := UnmarshalBBIBECiphertext(MarshalBBIBECiphertext(ciphertext))
+	if err != nil {
+		t.Fatal("解码密文失败:", err)
+	}
+	decryptedMessage, err := instance.Decrypt(decodedCiphertext, decodedKey)
+	if err != nil {
+		t.Fatal("解密失败:", err)
+	}
+	if !decryptedMessage.Message.Equal(&m) {
+		t.Fatal("解密结果与原始消息不一致")
+	}
+}
+
+// TestIBEEncodingInvalid 测试严格解码拒绝的各种输入。
+func TestIBEEncodingInvalid(t *testing.T) {
+	expectError := func(err error, target error) {
+		t.Helper()
+		var decodeError *DecodeError
+		if !errors.As(err, &decodeError) || !errors.Is(err, target) {
+			t.Fatalf("expected DecodeError wrapping %v, got %v", target, err)
+		}
+	}
+
+	pkg, err := NewBFIBEPKG()
+	if err != nil {
+		t.Fatal("创建PKG失败:", err)
+	}
+	publicParams, err := pkg.Setup()
+	if err != nil {
+		t.Fatal("系统初始化失败:", err)
+	}
+	secretKey, err := pkg.Extract(&BFIBEIdentity{Id: "ChenBerry"})
+	if err != nil {
+		t.Fatal("密钥提取失败:", err)
+	}
+	encodedParams := MarshalBFIBEPublicParams(publicParams)
+	encodedKey := MarshalBFIBESecretKey(secretKey)
+
+	// 头部: 版本、方案、曲线与对象类型
+	_, err = UnmarshalBFIBEPublicParams(encodedParams[:4])
+	expectError(err, ErrInvalidLength)
+	wrongVersion := append([]byte{}, encodedParams...)
+	wrongVersion[0] = EncodingVersion + 1
+	_, err = UnmarshalBFIBEPublicParams(wrongVersion)
+	expectError(err, ErrUnsupportedVersion)
+	wrongCurve := append([]byte{}, encodedParams...)
+	wrongCurve[3] = byte(CurveBN254 + 1)
+	_, err = UnmarshalBFIBEPublicParams(wrongCurve)
+	expectError(err, ErrUnexpectedType)
+	_, err = UnmarshalBFIBEPublicParams(encodedKey)
+	expectError(err, ErrUnexpectedType)
+	_, err = UnmarshalBBIBESecretKey(encodedKey)
+	expectError(err, ErrUnexpectedType)
+
+	// 长度: 截断、多余的字节、长度前缀超出数据
+	_, err = UnmarshalBFIBESecretKey(encodedKey[:len(encodedKey)-1])
+	expectError(err, ErrInvalidLength)
+	_, err = UnmarshalBFIBESecretKey(append(append([]byte{}, encodedKey...), 0))
+	expectError(err, ErrInvalidLength)
+	_, err = UnmarshalBFIBEPublicParams(append(append([]byte{}, encodedParams...), 0))
+	expectError(err, ErrInvalidLength)
+	_, err = UnmarshalBFIBEPublicParams(encodedParams[:len(encodedParams)-1])
+	expectError(err, ErrInvalidLength)
+	noDST := MarshalBFIBEPublicParams(&BFIBEPublicParams{G1: publicParams.G1, G1x: publicParams.G1x})
+	_, err = UnmarshalBFIBEPublicParams(noDST)
+	expectError(err, ErrInvalidEncoding)
+
+	// 点: 无穷远点、未压缩标记、不在子群中的G2点
+	infinityKey := MarshalBFIBESecretKey(&BFIBESecretKey{})
+	_, err = UnmarshalBFIBESecretKey(infinityKey)
+	expectError(err, ErrPointAtInfinity)
+	uncompressed := append([]byte{}, encodedKey...)
+	uncompressed[encodingHeaderSize] &^= 0xC0
+	_, err = UnmarshalBFIBESecretKey(uncompressed)
+	expectError(err, ErrInvalidEncoding)
+	var u bn254.E2
+	u.A0.SetUint64(7)
+	u.A1.SetUint64(11)
+	notInSubgroup := bn254.MapToCurve2(&u)
+	if !notInSubgroup.IsOnCurve() || notInSubgroup.IsInSubGroup() {
+		t.Fatal("test point was expected to be on the curve but outside the subgroup")
+	}
+	_, err = UnmarshalBFIBESecretKey(MarshalBFIBESecretKey(&BFIBESecretKey{sk: notInSubgroup}))
+	expectError(err, ErrNotInSubgroup)
+
+	// BB04: 超出范围的标量与不在GT中的元素
+	instance, err := NewBBIBEInstance()
+	if err != nil {
+		t.Fatal("创建IBE实例失败:", err)
+	}
+	bbParams, err := instance.SetUp()
+	if err != nil {
+		t.Fatal("系统初始化失败:", err)
+	}
+	bbKey, err := instance.KeyGenerate(&BBIBEIdentity{Id: big.NewInt(7)})
+	if err != nil {
+		t.Fatal("密钥生成失败:", err)
+	}
+	if _, err := MarshalBBIBESecretKey(&BBIBESecretKey{r: ecc.BN254.ScalarField(), k: bbKey.k}); !errors.Is(err, ErrInvalidScalar) {
+		t.Fatalf("expected ErrInvalidScalar, got %v", err)
+	}
+	encodedBBKey, err := MarshalBBIBESecretKey(bbKey)
+	if err != nil {
+		t.Fatal("编码私钥失败:", err)
+	}
+	outOfRange := append([]byte{}, encodedBBKey...)
+	ecc.BN254.ScalarField().FillBytes(outOfRange[encodingHeaderSize : encodingHeaderSize+scalarSize])
+	_, err = UnmarshalBBIBESecretKey(outOfRange)
+	expectError(err, ErrInvalidScalar)
+
+	randomElement, err := new(bn254.GT).SetRandom()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ciphertext, err := instance.Encrypt(&BBIBEMessage{Message: *randomElement}, &BBIBEIdentity{Id: big.NewInt(7)}, bbParams)
+	if err != nil {
+		t.Fatal("加密失败:", err)
+	}
+	_, err = UnmarshalBBIBECiphertext(MarshalBBIBECiphertext(ciphertext))
+	expectError(err, ErrNotInSubgroup)
+	_, err = UnmarshalBBIBECiphertext(MarshalBBIBECiphertext(&BBIBECiphertext{a: ciphertext.a, b: ciphertext.b}))
+	expectError(err, ErrNotInSubgroup)
+
+	// PEM: 块类型与头部不一致、多余的数据
+	paramsPEM, err := EncodePEM(encodedParams)
+	if err != nil {
+		t.Fatal("编码公共参数失败:", err)
+	}
+	relabeled := bytes.ReplaceAll(paramsPEM, []byte("BF01 IBE PUBLIC PARAMS"), []byte("BF01 IBE SECRET KEY"))
+	_, err = DecodePEM(relabeled)
+	expectError(err, ErrUnexpectedType)
+	_, err = DecodePEM(append(append([]byte{}, paramsPEM...), paramsPEM...))
+	expectError(err, ErrInvalidEncoding)
+	_, err = DecodePEM([]byte("not a PEM block"))
+	expectError(err, ErrInvalidEncoding)
+	if _, err := EncodePEM([]byte{EncodingVersion, byte(SchemeBB04), 0, byte(CurveBN254), byte(objectFullIdentCiphertext)}); err == nil {
+		t.Fatal("EncodePEM accepted an object type not defined for BB04")
+	}
+}
+
+// TestIBEEncodingCurveID 测试编码头部中的曲线标识固定为BN254 = 1,且与ecc.ID相互转换。
+func TestIBEEncodingCurveID(t *testing.T) {
+	pkg, err := NewBFIBEPKG()
+	if err != nil {
+		t.Fatal("创建PKG失败:", err)
+	}
+	publicParams, err := pkg.Setup()
+	if err != nil {
+		t.Fatal("系统初始化失败:", err)
+	}
+	encoded := MarshalBFIBEPublicParams(publicParams)
+	if encoded[2] != 0 || encoded[3] != 1 {
+		t.Fatalf("unexpected curve identifier %x", encoded[2:4])
+	}
+
+	id, err := CurveBN254.ECC()
+	if err != nil || id != ecc.BN254 {
+		t.Fatalf("CurveBN254.ECC() = %v, %v", id, err)
+	}
+	curve, err := CurveIDFromECC(ecc.BN254)
+	if err != nil || curve != CurveBN254 {
+		t.Fatalf("CurveIDFromECC(BN254) = %v, %v", curve, err)
+	}
+	if _, err := CurveIDFromECC(ecc.BLS12_381); !errors.Is(err, ErrUnexpectedType) {
+		t.Fatalf("CurveIDFromECC(BLS12_381) returned %v, expected ErrUnexpectedType", err)
+	}
+	if _, err := CurveID(0).ECC(); !errors.Is(err, ErrUnexpectedType) {
+		t.Fatalf("CurveID(0).ECC() returned %v, expected ErrUnexpectedType", err)
+	}
+}
@@ -8,7 +8,11 @@ import (
 // ComputeLagrangeBasis 计算拉格朗日基函数在 x 处的值：Delta_{i, S}(x) mod q
 func ComputeLagrangeBasis(i int, s []int, x int) *big.Int {
 	// q: 有限域的阶。ecc.BN254.ScalarField()
-	q := ecc.BN254.ScalarField()
+	return ComputeLagrangeBasisMod(i, s, x, ecc.BN254.ScalarField())
+}
+
+// ComputeLagrangeBasisMod 与ComputeLagrangeBasis相同，但在阶为 q 的有限域上计算
+func ComputeLagrangeBasisMod(i int, s []int, x int, q *big.Int) *big.Int {
 	iElement := big.NewInt(int64(i))
 	xElement := big.NewInt(int64(x))
 	delta := big.NewInt(1)
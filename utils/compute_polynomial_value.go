@@ -13,7 +13,11 @@ import (
 // 返回值: P(x) mod q 的计算结果 (*big.Int)
 
 func ComputePolynomialValue(coefficient []*big.Int, x *big.Int) *big.Int {
-	q := ecc.BN254.ScalarField()
+	return ComputePolynomialValueMod(coefficient, x, ecc.BN254.ScalarField())
+}
+
+// ComputePolynomialValueMod 与ComputePolynomialValue相同，但在阶为 q 的有限域上计算。
+func ComputePolynomialValueMod(coefficient []*big.Int, x *big.Int, q *big.Int) *big.Int {
 
 	if len(coefficient) == 0 {
 		return big.NewInt(0)
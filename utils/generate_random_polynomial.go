@@ -13,7 +13,11 @@ import (
 // constantTerm: 多项式的常数项系数 a_0。
 // 返回值:   一个 []*big.Int 数组，表示多项式的系数。
 func GenerateRandomPolynomial(degree int, constantTerm *big.Int) []*big.Int {
-	q := ecc.BN254.ScalarField()
+	return GenerateRandomPolynomialMod(degree, constantTerm, ecc.BN254.ScalarField())
+}
+
+// GenerateRandomPolynomialMod 与GenerateRandomPolynomial相同，但随机系数取自阶为 q 的有限域。
+func GenerateRandomPolynomialMod(degree int, constantTerm *big.Int, q *big.Int) []*big.Int {
 	if degree <= 0 {
 		return []*big.Int{}
 	}
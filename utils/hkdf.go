@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"fmt"
+)
+
+// HKDFExtract 实现RFC 5869中基于HMAC-SHA256的HKDF-Extract步骤: PRK = HMAC(salt, IKM)
+// salt为空时按规范使用全零的HashLen字节作为salt。
+func HKDFExtract(salt []byte, ikm []byte) []byte {
+	if len(salt) == 0 {
+		salt = make([]byte, sha256.Size)
+	}
+	mac := hmac.New(sha256.New, salt)
+	mac.Write(ikm)
+	return mac.Sum(nil)
+}
+
+// HKDFExpand 实现RFC 5869中基于HMAC-SHA256的HKDF-Expand步骤,输出length字节的OKM。
+// T(0) = 空串, T(i) = HMAC(PRK, T(i-1) || info || i), OKM = T(1) || T(2) || ...
+// length不能超过255*HashLen。
+func HKDFExpand(prk []byte, info []byte, length int) ([]byte, error) {
+	if length < 0 || length > 255*sha256.Size {
+		return nil, fmt.Errorf("invalid hkdf output length: %d", length)
+	}
+	okm := make([]byte, 0, length+sha256.Size)
+	var t []byte
+	for i := 1; len(okm) < length; i++ {
+		mac := hmac.New(sha256.New, prk)
+		mac.Write(t)
+		mac.Write(info)
+		mac.Write([]byte{byte(i)})
+		t = mac.Sum(nil)
+		okm = append(okm, t...)
+	}
+	return okm[:length], nil
+}
@@ -0,0 +1,28 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+// TestHKDF 使用RFC 5869附录A.1的已知答案测试HKDF-SHA256。
+func TestHKDF(t *testing.T) {
+	ikm, _ := hex.DecodeString("0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b")
+	salt, _ := hex.DecodeString("000102030405060708090a0b0c")
+	info, _ := hex.DecodeString("f0f1f2f3f4f5f6f7f8f9")
+	expectedPRK, _ := hex.DecodeString("077709362c2e32df0ddc3f0dc47bba6390b6c73bb50f9c3122ec844ad7c2b3e5")
+	expectedOKM, _ := hex.DecodeString("3cb25f25faacd57a90434f64d0362f2a2d2d0a90cf1a5a4c5db02d56ecc4c5bf34007208d5b887185865")
+
+	prk := HKDFExtract(salt, ikm)
+	if !bytes.Equal(prk, expectedPRK) {
+		t.Fatalf("HKDFExtract mismatch: %x", prk)
+	}
+	okm, err := HKDFExpand(prk, info, 42)
+	if err != nil {
+		t.Fatalf("HKDFExpand failed: %v", err)
+	}
+	if !bytes.Equal(okm, expectedOKM) {
+		t.Fatalf("HKDFExpand mismatch: %x", okm)
+	}
+}
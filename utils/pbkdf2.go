@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+)
+
+// PBKDF2 实现RFC 8018中基于HMAC-SHA256的PBKDF2,由口令和盐派生keyLength字节的密钥。
+// T_i = U_1 xor U_2 xor ... xor U_c, U_1 = HMAC(P, S || INT(i)), U_j = HMAC(P, U_{j-1})
+func PBKDF2(password []byte, salt []byte, iterations int, keyLength int) ([]byte, error) {
+	if iterations < 1 || keyLength < 1 {
+		return nil, fmt.Errorf("invalid pbkdf2 parameters: %d iterations, %d bytes", iterations, keyLength)
+	}
+	mac := hmac.New(sha256.New, password)
+	key := make([]byte, 0, keyLength+sha256.Size)
+	var counter [4]byte
+	for block := uint32(1); len(key) < keyLength; block++ {
+		binary.BigEndian.PutUint32(counter[:], block)
+		mac.Reset()
+		mac.Write(salt)
+		mac.Write(counter[:])
+		u := mac.Sum(nil)
+		t := append([]byte{}, u...)
+		for i := 1; i < iterations; i++ {
+			mac.Reset()
+			mac.Write(u)
+			u = mac.Sum(u[:0])
+			for j := range t {
+				t[j] ^= u[j]
+			}
+		}
+		key = append(key, t...)
+	}
+	return key[:keyLength], nil
+}
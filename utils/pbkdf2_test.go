@@ -0,0 +1,30 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+// TestPBKDF2 使用RFC 7914第11节的已知答案测试PBKDF2-HMAC-SHA256。
+func TestPBKDF2(t *testing.T) {
+	vectors := []struct {
+		password   string
+		salt       string
+		iterations int
+		expected   string
+	}{
+		{"passwd", "salt", 1, "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc49ca9cccf179b645991664b39d77ef317c71b845b1e30bd509112041d3a19783"},
+		{"Password", "NaCl", 80000, "4ddcd8f60b98be21830cee5ef22701f9641a4418d04c0414aeff08876b34ab56a1d425a1225833549adb841b51c9b3176a272bdebba1d078478f62b397f33c8d"},
+	}
+	for _, vector := range vectors {
+		expected, _ := hex.DecodeString(vector.expected)
+		key, err := PBKDF2([]byte(vector.password), []byte(vector.salt), vector.iterations, 64)
+		if err != nil {
+			t.Fatalf("PBKDF2 failed: %v", err)
+		}
+		if !bytes.Equal(key, expected) {
+			t.Fatalf("PBKDF2(%q, %q, %d) mismatch: %x", vector.password, vector.salt, vector.iterations, key)
+		}
+	}
+}
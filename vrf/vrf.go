@@ -0,0 +1,167 @@
+package vrf
+
+// 参考文献:
+// S. Micali, M. Rabin, S. Vadhan. "Verifiable Random Functions." FOCS 1999.
+// D. Boneh, B. Lynn, H. Shacham. "Short Signatures from the Weil Pairing." ASIACRYPT 2001.
+//
+// BLS签名是唯一的: 对于有效的公钥和给定的输入,只存在一个能通过验证的签名,因此可以直接作为VRF:
+//   - 证明: π = h(α)^sk,即使用VRF专用DST的BLS签名(G2上的点,使用压缩编码,BN254上为64字节)
+//   - 验证: e(pk, h(α)) = e(g1, π),且pk和π都必须位于素数阶子群中且不是无穷远点
+//   - 输出: β = SHA-256(proofToHashDST || π),固定为32字节
+//
+// 公钥与私钥直接使用bls包的BLSKeyPair,曲线由调用者传入的BLSParams.Curve决定,
+// hash-to-curve使用与普通签名不同的DST,因此VRF证明不能作为普通BLS签名使用,反之亦然。
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	bw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761"
+	"github.com/mmsyan/GnarkPairingProject/bls"
+	"math/big"
+)
+
+// OutputSize 为VRF输出的字节数。
+const OutputSize = sha256.Size
+
+// vrfSuite 为一条曲线上VRF使用的DST与证明长度。
+type vrfSuite struct {
+	hashToCurveDST string
+	proofToHashDST string
+	proofSize      int
+}
+
+var suites = map[ecc.ID]vrfSuite{
+	ecc.BN254: {
+		hashToCurveDST: "BLS_VRF_BN254G2_XMD:SHA-256_SVDW_RO_",
+		proofToHashDST: "BLS_VRF_BN254_PROOF_TO_HASH_",
+		proofSize:      bn254.SizeOfG2AffineCompressed,
+	},
+	ecc.BLS12_381: {
+		hashToCurveDST: "BLS_VRF_BLS12381G2_XMD:SHA-256_SSWU_RO_",
+		proofToHashDST: "BLS_VRF_BLS12381_PROOF_TO_HASH_",
+		proofSize:      bls12381.SizeOfG2AffineCompressed,
+	},
+	ecc.BLS12_377: {
+		hashToCurveDST: "BLS_VRF_BLS12377G2_XMD:SHA-256_SSWU_RO_",
+		proofToHashDST: "BLS_VRF_BLS12377_PROOF_TO_HASH_",
+		proofSize:      bls12377.SizeOfG2AffineCompressed,
+	},
+	ecc.BW6_761: {
+		hashToCurveDST: "BLS_VRF_BW6761G2_XMD:SHA-256_SSWU_RO_",
+		proofToHashDST: "BLS_VRF_BW6761_PROOF_TO_HASH_",
+		proofSize:      bw6761.SizeOfG2AffineCompressed,
+	},
+}
+
+// ProofSize 返回参数曲线上VRF证明的字节数(压缩的G2点),不支持的曲线返回0。
+func ProofSize(blsParams bls.BLSParams) int {
+	suite, err := suiteOf(blsParams)
+	if err != nil {
+		return 0
+	}
+	return suite.proofSize
+}
+
+// Prove 使用私钥计算输入alpha的VRF证明,blsParams为bls.SetUp或bls.SetUpOnCurve返回的参数。
+func Prove(blsParams bls.BLSParams, privateKey *big.Int, alpha []byte) ([]byte, error) {
+	params, _, err := vrfParams(blsParams)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute vrf proof: %v", err)
+	}
+	if err := bls.ValidatePrivateKey(*params, privateKey); err != nil {
+		return nil, fmt.Errorf("failed to compute vrf proof: %w", err)
+	}
+	gamma, err := bls.DetachedSign(*params, privateKey, alpha)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute vrf proof: %v", err)
+	}
+	return bls.MarshalSignature(*gamma, true), nil
+}
+
+// Verify 验证输入alpha的VRF证明,公钥必须通过bls.KeyValidate检查,证明的编码无效时返回解码错误。
+// 只有Verify返回true之后,ProofToHash的输出才可以作为alpha的随机数使用。
+func Verify(blsParams bls.BLSParams, publicKey bls.G1Point, alpha []byte, proof []byte) (bool, error) {
+	params, suite, err := vrfParams(blsParams)
+	if err != nil {
+		return false, fmt.Errorf("failed to verify vrf proof: %v", err)
+	}
+	// 无穷远点作为公钥时任何输入的证明都是无穷远点,输出不再随机
+	if !bls.KeyValidate(*params, publicKey) {
+		return false, nil
+	}
+	gamma, err := decodeProof(*params, suite, proof)
+	if err != nil {
+		return false, fmt.Errorf("failed to verify vrf proof: %w", err)
+	}
+	isValid, err := bls.DetachedVerify(*params, publicKey, alpha, *gamma)
+	if err != nil {
+		return false, fmt.Errorf("failed to verify vrf proof: %v", err)
+	}
+	return isValid, nil
+}
+
+// ProofToHash 由VRF证明计算32字节的VRF输出β,证明的编码必须有效。
+func ProofToHash(blsParams bls.BLSParams, proof []byte) ([]byte, error) {
+	params, suite, err := vrfParams(blsParams)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute vrf output: %v", err)
+	}
+	gamma, err := decodeProof(*params, suite, proof)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute vrf output: %v", err)
+	}
+	// 使用规范的压缩编码,保证同一个证明只有一个输出
+	encoded := gamma.Bytes()
+	h := sha256.New()
+	h.Write([]byte(suite.proofToHashDST))
+	h.Write(encoded)
+	return h.Sum(nil), nil
+}
+
+// Evaluate 计算VRF证明及对应的输出,等价于Prove之后调用ProofToHash。
+func Evaluate(blsParams bls.BLSParams, privateKey *big.Int, alpha []byte) ([]byte, []byte, error) {
+	proof, err := Prove(blsParams, privateKey, alpha)
+	if err != nil {
+		return nil, nil, err
+	}
+	beta, err := ProofToHash(blsParams, proof)
+	if err != nil {
+		return nil, nil, err
+	}
+	return proof, beta, nil
+}
+
+func decodeProof(params bls.BLSParams, suite vrfSuite, proof []byte) (*bls.G2Point, error) {
+	if len(proof) != suite.proofSize {
+		return nil, &bls.DecodeError{Object: "vrf proof", Err: bls.ErrInvalidLength}
+	}
+	return bls.UnmarshalSignature(params, proof)
+}
+
+// suiteOf 返回参数曲线上的VRF参数,与bls包一致,未设置曲线的参数视为BN254。
+func suiteOf(blsParams bls.BLSParams) (vrfSuite, error) {
+	curve := blsParams.Curve
+	if curve == ecc.UNKNOWN {
+		curve = ecc.BN254
+	}
+	suite, ok := suites[curve]
+	if !ok {
+		return vrfSuite{}, fmt.Errorf("unsupported curve: %v", curve)
+	}
+	return suite, nil
+}
+
+// vrfParams 返回计算VRF证明使用的BLS参数,签名DST替换为该曲线上VRF专用的DST,调用者的参数不会被修改。
+func vrfParams(blsParams bls.BLSParams) (*bls.BLSParams, vrfSuite, error) {
+	suite, err := suiteOf(blsParams)
+	if err != nil {
+		return nil, vrfSuite{}, err
+	}
+	params := blsParams
+	params.DST = []byte(suite.hashToCurveDST)
+	return &params, suite, nil
+}
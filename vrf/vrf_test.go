@@ -0,0 +1,136 @@
+package vrf
+
+import (
+	"bytes"
+	"errors"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/mmsyan/GnarkPairingProject/bls"
+	"testing"
+)
+
+func newKeyPair(t *testing.T) (*bls.BLSParams, *bls.BLSKeyPair) {
+	params, err := bls.SetUp()
+	if err != nil {
+		t.Fatalf("SetUp failed: %v", err)
+	}
+	keyPair, err := bls.KeyGeneration(*params)
+	if err != nil {
+		t.Fatalf("KeyGeneration failed: %v", err)
+	}
+	return params, keyPair
+}
+
+// TestVRF 测试VRF的证明、验证与输出,以及输出的确定性。
+func TestVRF(t *testing.T) {
+	params, keyPair := newKeyPair(t)
+	alpha := []byte("round 7 leader election")
+
+	proof, beta, err := Evaluate(*params, keyPair.PrivateKey, alpha)
+	if err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+	if len(proof) != ProofSize(*params) || len(beta) != OutputSize {
+		t.Fatalf("unexpected proof/output length: %d/%d", len(proof), len(beta))
+	}
+	isValid, err := Verify(*params, keyPair.PublicKey, alpha, proof)
+	if err != nil || !isValid {
+		t.Fatalf("Verify failed: %v", err)
+	}
+
+	// 相同输入的证明和输出唯一,不同输入的输出不同
+	proof2, beta2, err := Evaluate(*params, keyPair.PrivateKey, alpha)
+	if err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+	if !bytes.Equal(proof, proof2) || !bytes.Equal(beta, beta2) {
+		t.Fatal("the vrf is not deterministic")
+	}
+	_, beta3, err := Evaluate(*params, keyPair.PrivateKey, []byte("round 8 leader election"))
+	if err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+	if bytes.Equal(beta, beta3) {
+		t.Fatal("different inputs produced the same output")
+	}
+
+	isValid, err = Verify(*params, keyPair.PublicKey, []byte("other input"), proof)
+	if err != nil || isValid {
+		t.Fatal("Verify accepted a proof for a different input")
+	}
+	_, otherKeyPair := newKeyPair(t)
+	isValid, err = Verify(*params, otherKeyPair.PublicKey, alpha, proof)
+	if err != nil || isValid {
+		t.Fatal("Verify accepted a proof under a different key")
+	}
+}
+
+// TestVRFDomainSeparation 测试VRF证明与普通BLS签名互不通用。
+func TestVRFDomainSeparation(t *testing.T) {
+	params, keyPair := newKeyPair(t)
+	alpha := []byte("input")
+	signature, err := bls.DetachedSign(*params, keyPair.PrivateKey, alpha)
+	if err != nil {
+		t.Fatalf("DetachedSign failed: %v", err)
+	}
+	isValid, err := Verify(*params, keyPair.PublicKey, alpha, bls.MarshalSignature(*signature, true))
+	if err != nil || isValid {
+		t.Fatal("a plain bls signature was accepted as a vrf proof")
+	}
+}
+
+// TestVRFInvalid 测试无效的公钥与证明编码。
+func TestVRFInvalid(t *testing.T) {
+	params, keyPair := newKeyPair(t)
+	alpha := []byte("input")
+	proof, err := Prove(*params, keyPair.PrivateKey, alpha)
+	if err != nil {
+		t.Fatalf("Prove failed: %v", err)
+	}
+
+	// 公钥为无穷远点时,无穷远点证明对任何输入都能通过配对检查,必须被拒绝
+	infinity := bn254.G2Affine{}
+	isValid, err := Verify(*params, bls.NewG1Point(bn254.G1Affine{}), alpha, bls.MarshalSignature(bls.NewG2Point(infinity), true))
+	if err != nil || isValid {
+		t.Fatal("Verify accepted the point at infinity as public key")
+	}
+	isValid, err = Verify(*params, keyPair.PublicKey, alpha, proof[:ProofSize(*params)-1])
+	if !errors.Is(err, bls.ErrInvalidLength) || isValid {
+		t.Fatalf("Verify was expected to reject a truncated proof, got %v", err)
+	}
+	if _, err := ProofToHash(*params, proof[:ProofSize(*params)-1]); err == nil {
+		t.Fatal("ProofToHash was expected to reject a truncated proof")
+	}
+	if _, err := Prove(*params, nil, alpha); !errors.Is(err, bls.ErrInvalidPrivateKey) {
+		t.Fatalf("Prove was expected to reject a nil private key, got %v", err)
+	}
+}
+
+// TestVRFOnCurves 测试VRF在bls包支持的每条曲线上都可以使用,证明长度由参数曲线决定。
+func TestVRFOnCurves(t *testing.T) {
+	alpha := []byte("input")
+	for _, curve := range bls.SupportedCurves() {
+		params, err := bls.SetUpOnCurve(curve)
+		if err != nil {
+			t.Fatalf("SetUpOnCurve failed: %v", err)
+		}
+		keyPair, err := bls.KeyGeneration(*params)
+		if err != nil {
+			t.Fatalf("KeyGeneration failed: %v", err)
+		}
+		proof, beta, err := Evaluate(*params, keyPair.PrivateKey, alpha)
+		if err != nil {
+			t.Fatalf("%v: Evaluate failed: %v", curve, err)
+		}
+		if len(proof) != ProofSize(*params) || len(beta) != OutputSize {
+			t.Fatalf("%v: unexpected proof/output length: %d/%d", curve, len(proof), len(beta))
+		}
+		isValid, err := Verify(*params, keyPair.PublicKey, alpha, proof)
+		if err != nil || !isValid {
+			t.Fatalf("%v: Verify failed: %v", curve, err)
+		}
+		// VRF参数中的DST只在内部替换,调用者的参数仍可用于普通签名
+		if string(params.DST) == "" || bytes.HasPrefix(params.DST, []byte("BLS_VRF_")) {
+			t.Fatalf("%v: Evaluate modified the caller's params", curve)
+		}
+	}
+}